@@ -0,0 +1,248 @@
+// Package analyzer detects unused imports, definitions and parameters in
+// Python, Go, Ruby, PHP, Astro, Svelte and Vue sources.
+//
+// A MultiLangAnalyzer created with New can analyze a single file with Analyze
+// or a set of files together with AnalyzeWorkspace, in which case names used
+// from other files are not reported. Results are cached per filename and
+// content hash, so repeated calls with unchanged files are cheap.
+package analyzer
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+type CacheEntry struct {
+	hash   string
+	config string
+	result AnalysisResult
+}
+
+const analyzerCacheVersion = "2026-10-17-identifier-index"
+
+// MultiLangAnalyzer is safe for concurrent use.
+type MultiLangAnalyzer struct {
+	mu          sync.Mutex
+	cache       map[string]CacheEntry
+	parsedFiles map[string]ParsedWorkspaceEntry
+	ws          *workspace
+	// useTick counts parse cache lookups to order entries by last use.
+	useTick int64
+}
+
+// WorkspaceAnalysisResult holds the per-file results of AnalyzeWorkspace,
+// keyed by filename.
+type WorkspaceAnalysisResult struct {
+	Results map[string]AnalysisResult
+}
+
+// ParsedWorkspaceEntry is a cached parse. Words holds the identifiers of
+// the parsed Code for the workspace's reverse index, see identifiers; it is
+// not saved with the parse cache but rebuilt on load.
+type ParsedWorkspaceEntry struct {
+	Version string   `json:"version"`
+	Hash    string   `json:"hash"`
+	Lang    Language `json:"lang"`
+	ParsedFile
+	Words map[string]bool `json:"-"`
+
+	// lastUsed orders entries for eviction, see ParseCacheLimits.
+	lastUsed int64
+}
+
+// New returns an analyzer with empty caches.
+func New() *MultiLangAnalyzer {
+	return &MultiLangAnalyzer{
+		cache:       make(map[string]CacheEntry),
+		parsedFiles: make(map[string]ParsedWorkspaceEntry),
+	}
+}
+
+// Analyze reports unused code in a single file without looking at any other
+// file. When req.Hash is empty a hash of the content is used as cache key.
+func (a *MultiLangAnalyzer) Analyze(req AnalyzeRequest) AnalysisResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	hash := req.Hash
+	if hash == "" {
+		hash = contentHash(req.Content)
+	}
+
+	config := configKey(req.Config)
+	if entry, ok := a.cache[req.Filename]; ok {
+		if entry.hash == hash && entry.config == config {
+			return entry.result
+		}
+	}
+
+	la := languageFor(req.Filename)
+	if la == nil {
+		return AnalysisResult{}
+	}
+
+	parsed := la.Parse(req.Content, req.Filename)
+	if !la.StandaloneDefinitions() {
+		parsed.Definitions = nil
+	}
+	file := AnalyzeFile{Content: req.Content, Filename: req.Filename, Hash: hash}
+	result := BuildAnalysisResult(la, file, parsed, nil, req.Config)
+
+	a.cache[req.Filename] = CacheEntry{hash: hash, config: config, result: result}
+	return result
+}
+
+// AnalyzeWorkspace analyzes all files of req together, so that definitions
+// and imports referenced from another file are not reported as unused. The
+// workspace is kept for the next call, which only re-analyzes what its
+// changes affect, see workspace.go.
+func (a *MultiLangAnalyzer) AnalyzeWorkspace(req WorkspaceAnalyzeRequest) WorkspaceAnalysisResult {
+	result, _ := a.AnalyzeWorkspaceContext(context.Background(), req, nil)
+	return result
+}
+
+// AnalyzeWorkspaceContext is AnalyzeWorkspace reporting its progress to
+// progress, which may be nil, and stopping when ctx is done. It then returns
+// ctx.Err() and no results. The files parsed until then stay cached; the
+// previous workspace is kept when the scan is cancelled while parsing and
+// dropped otherwise, so that the next call analyzes it again from scratch.
+func (a *MultiLangAnalyzer) AnalyzeWorkspaceContext(ctx context.Context, req WorkspaceAnalyzeRequest, progress ProgressFunc) (WorkspaceAnalysisResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	files := withContentHashes(req.Files)
+	ws := a.ws
+	if ws == nil || configKey(ws.config) != configKey(req.Config) || ws.root != req.Root {
+		ws = newWorkspace(req.Config, req.Root)
+	}
+
+	var changed []AnalyzeFile
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[file.Filename] = true
+		if old, ok := ws.files[file.Filename]; !ok || old.Hash != file.Hash {
+			changed = append(changed, file)
+		}
+	}
+	var removed []string
+	for filename := range ws.files {
+		if !present[filename] {
+			removed = append(removed, filename)
+		}
+	}
+	if _, err := a.update(ctx, ws, changed, removed, progress); err != nil {
+		return WorkspaceAnalysisResult{}, err
+	}
+
+	results := make(map[string]AnalysisResult, len(a.ws.results))
+	for filename, res := range a.ws.results {
+		results[filename] = res
+	}
+	if req.Diff != "" {
+		return ParseUnifiedDiff(req.Diff).Filter(WorkspaceAnalysisResult{Results: results}, req.Root), nil
+	}
+	return WorkspaceAnalysisResult{Results: results}, nil
+}
+
+// UpdateFiles applies changed and removed files to the workspace of the
+// previous AnalyzeWorkspace call, or to an empty one, keeping its Config
+// and Root. Only the changed files are parsed again, and the result only
+// holds the files whose results may have changed: the changed ones and
+// those whose names are affected by the change. Removed files have no
+// result.
+func (a *MultiLangAnalyzer) UpdateFiles(changed []AnalyzeFile, removed []string) WorkspaceAnalysisResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ws := a.ws
+	if ws == nil {
+		ws = newWorkspace(nil, "")
+	}
+	rebuilt, _ := a.update(context.Background(), ws, withContentHashes(changed), removed, nil)
+	results := make(map[string]AnalysisResult, len(rebuilt))
+	for _, filename := range rebuilt {
+		results[filename] = a.ws.results[filename]
+	}
+	return WorkspaceAnalysisResult{Results: results}
+}
+
+// parseFiles brings the parse cache up to date for files, parsing the
+// files that changed concurrently, see forEach. When ctx is done it stops
+// and returns ctx.Err(), keeping the parses finished so far.
+func (a *MultiLangAnalyzer) parseFiles(ctx context.Context, files []AnalyzeFile, progress ProgressFunc) error {
+	counter := newProgressCounter(progress, PhaseParse, len(files))
+	entries := make([]*ParsedWorkspaceEntry, len(files))
+	forEach(len(files), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		defer counter.add(1)
+		la := languageFor(files[i].Filename)
+		if la == nil {
+			return
+		}
+		if _, ok := a.cachedParse(files[i], la); !ok {
+			entries[i] = parseWorkspaceEntry(files[i], la)
+		}
+	})
+	for i, entry := range entries {
+		if entry != nil {
+			a.parsedFiles[files[i].Filename] = *entry
+		}
+	}
+	return ctx.Err()
+}
+
+// getParsedWorkspaceData parses file with la, reusing the previous parse when
+// the file is unchanged. The returned Code has comments already stripped.
+func (a *MultiLangAnalyzer) getParsedWorkspaceData(file AnalyzeFile, la LanguageAnalyzer) ParsedWorkspaceEntry {
+	entry, ok := a.cachedParse(file, la)
+	if !ok {
+		entry = *parseWorkspaceEntry(file, la)
+	}
+	a.useTick++
+	entry.lastUsed = a.useTick
+	a.parsedFiles[file.Filename] = entry
+	return entry
+}
+
+func (a *MultiLangAnalyzer) cachedParse(file AnalyzeFile, la LanguageAnalyzer) (ParsedWorkspaceEntry, bool) {
+	cached, ok := a.parsedFiles[file.Filename]
+	if ok && cached.Version == analyzerCacheVersion && cached.Hash == file.Hash && cached.Lang == la.Language() {
+		return cached, true
+	}
+	return ParsedWorkspaceEntry{}, false
+}
+
+func parseWorkspaceEntry(file AnalyzeFile, la LanguageAnalyzer) *ParsedWorkspaceEntry {
+	parsed := la.Parse(file.Content, file.Filename)
+	parsed.Code = stripComments(parsed.Code, la.Comments())
+	return &ParsedWorkspaceEntry{
+		Version:    analyzerCacheVersion,
+		Hash:       file.Hash,
+		Lang:       la.Language(),
+		ParsedFile: parsed,
+		Words:      identifiers(la, parsed.Code),
+	}
+}
+
+func contentHash(content string) string {
+	h := fnv.New64a()
+	h.Write([]byte(content))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// withContentHashes returns files with every empty Hash filled in, copying the
+// slice so the caller's request is left untouched.
+func withContentHashes(files []AnalyzeFile) []AnalyzeFile {
+	out := make([]AnalyzeFile, len(files))
+	for i, f := range files {
+		if f.Hash == "" {
+			f.Hash = contentHash(f.Content)
+		}
+		out[i] = f
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// benchmarkWorkspace generates a workspace of n modules in every built-in
+// language. Each module defines a few names, uses some from the module
+// before it, the first one from the last one except in Go, where import
+// cycles are not allowed, and leaves others unused, so that both the parse
+// and the cross-file usage checks have work to do.
+func benchmarkWorkspace(n int) []AnalyzeFile {
+	files := []AnalyzeFile{{Filename: "go.mod", Content: "module example.com/bench\n\ngo 1.22\n"}}
+	add := func(name, content string) {
+		files = append(files, AnalyzeFile{Filename: name, Content: content})
+	}
+	for i := 0; i < n; i++ {
+		prev := (i + n - 1) % n
+
+		var py strings.Builder
+		fmt.Fprintf(&py, "import os\nimport sys\nfrom mod%d import helper%d\n\n", prev, prev)
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&py, "def func%d_%d(a, b):\n    value = a * 2\n    return helper%d(value)\n\n", i, j, prev)
+		}
+		fmt.Fprintf(&py, "def helper%d(x, unused):\n    return os.path.join(str(x))\n", i)
+		add(fmt.Sprintf("py/mod%d.py", i), py.String())
+
+		var gosrc strings.Builder
+		helper := "Helper"
+		fmt.Fprintf(&gosrc, "package pkg%d\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n", i)
+		if i > 0 {
+			helper = fmt.Sprintf("pkg%d.Helper", prev)
+			fmt.Fprintf(&gosrc, "\n\t\"example.com/bench/go/pkg%d\"\n", prev)
+		}
+		gosrc.WriteString(")\n\n")
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&gosrc, "func Func%d(a, b int) string {\n\treturn fmt.Sprint(a) + %s(a)\n}\n\n", j, helper)
+		}
+		gosrc.WriteString("func Helper(x int) string {\n\treturn strings.Repeat(\"x\", x)\n}\n\nfunc unused(y int) {}\n")
+		add(fmt.Sprintf("go/pkg%d/pkg.go", i), gosrc.String())
+
+		var rb strings.Builder
+		fmt.Fprintf(&rb, "require 'set'\nrequire_relative 'mod%d'\n\nclass Mod%d\n", prev, i)
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&rb, "  def method%d(a, b)\n    Mod%d.new.helper(a)\n  end\n\n", j, prev)
+		}
+		rb.WriteString("  def helper(x)\n    x\n  end\nend\n")
+		add(fmt.Sprintf("rb/mod%d.rb", i), rb.String())
+
+		var php strings.Builder
+		fmt.Fprintf(&php, "<?php\nnamespace App;\n\nuse App\\Mod%d;\nuse App\\Missing;\n\nclass Mod%d {\n", prev, i)
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&php, "    public function method%d($a, $b) {\n        return (new Mod%d())->helper($a);\n    }\n\n", j, prev)
+		}
+		php.WriteString("    public function helper($x) {\n        return $x;\n    }\n}\n")
+		add(fmt.Sprintf("php/Mod%d.php", i), php.String())
+
+		add(fmt.Sprintf("web/Comp%d.svelte", i), fmt.Sprintf(
+			"<script>\n  import Comp%d from './Comp%d.svelte';\n  import { onMount } from 'svelte';\n  export let title = '';\n  let count = 0;\n  let unused = 1;\n  function increment(step) {\n    count += 1;\n  }\n</script>\n\n<h1>{title}</h1>\n<button on:click={increment}>{count}</button>\n<Comp%d />\n",
+			prev, prev, prev))
+	}
+	return files
+}
+
+// BenchmarkAnalyzeWorkspace measures a cold workspace scan, parsing every
+// file, on one worker and on GOMAXPROCS workers, see forEach.
+func BenchmarkAnalyzeWorkspace(b *testing.B) {
+	files := benchmarkWorkspace(200)
+	for _, bm := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"parallel", runtime.GOMAXPROCS(0)},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(bm.workers))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: files})
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// BaselineFileName is the baseline file the command line uses when no
+// other file is given.
+const BaselineFileName = ".unused-baseline.json"
+
+const baselineVersion = 1
+
+// Baseline is a snapshot of findings, usually stored in BaselineFileName,
+// that are accepted for now, so that later scans of a legacy code base
+// only report what is new. Findings are matched by issue ID, which does
+// not change when code moves to another line or the scan starts from
+// another directory, see issueID.
+type Baseline struct {
+	Version int             `json:"version"`
+	Issues  []BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue identifies an accepted finding. Only ID is compared; the
+// other fields keep the file readable in reviews. File is relative to the
+// root the findings were analyzed in, as the ID is.
+type BaselineIssue struct {
+	ID     string `json:"id"`
+	File   string `json:"file"`
+	RuleID string `json:"ruleId"`
+	Symbol string `json:"symbol"`
+}
+
+// NewBaseline records every finding of result, which was analyzed with
+// root as WorkspaceAnalyzeRequest.Root.
+func NewBaseline(result WorkspaceAnalysisResult, root string) *Baseline {
+	b := &Baseline{Version: baselineVersion, Issues: []BaselineIssue{}}
+	for _, res := range result.Results {
+		for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters, res.Exports, res.Files} {
+			for _, issue := range issues {
+				b.Issues = append(b.Issues, BaselineIssue{
+					ID:     issue.ID,
+					File:   relativePath(root, issue.File),
+					RuleID: issue.RuleID,
+					Symbol: issue.Symbol,
+				})
+			}
+		}
+	}
+	sort.Slice(b.Issues, func(i, j int) bool {
+		if b.Issues[i].File != b.Issues[j].File {
+			return b.Issues[i].File < b.Issues[j].File
+		}
+		return b.Issues[i].ID < b.Issues[j].ID
+	})
+	return b
+}
+
+// ParseBaseline decodes a baseline written by NewBaseline.
+func ParseBaseline(data []byte) (*Baseline, error) {
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %w", err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d", b.Version)
+	}
+	return &b, nil
+}
+
+// Filter returns result without the findings recorded in b, and the entries
+// of b that result no longer contains. A nil Baseline filters nothing.
+func (b *Baseline) Filter(result WorkspaceAnalysisResult) (WorkspaceAnalysisResult, []BaselineIssue) {
+	if b == nil {
+		return result, nil
+	}
+	known := make(map[string]bool, len(b.Issues))
+	for _, issue := range b.Issues {
+		known[issue.ID] = true
+	}
+
+	seen := make(map[string]bool)
+	keep := func(issues []CodeIssue) []CodeIssue {
+		out := []CodeIssue{}
+		for _, issue := range issues {
+			if known[issue.ID] {
+				seen[issue.ID] = true
+				continue
+			}
+			out = append(out, issue)
+		}
+		return out
+	}
+
+	filtered := WorkspaceAnalysisResult{Results: make(map[string]AnalysisResult, len(result.Results))}
+	for filename, res := range result.Results {
+		filtered.Results[filename] = AnalysisResult{
+			Imports:    keep(res.Imports),
+			Variables:  keep(res.Variables),
+			Parameters: keep(res.Parameters),
+			Exports:    keep(res.Exports),
+			Files:      keep(res.Files),
+		}
+	}
+
+	var fixed []BaselineIssue
+	for _, issue := range b.Issues {
+		if !seen[issue.ID] {
+			fixed = append(fixed, issue)
+		}
+	}
+	return filtered, fixed
+}
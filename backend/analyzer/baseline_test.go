@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRelativePath(t *testing.T) {
+	tests := []struct {
+		root     string
+		filename string
+		want     string
+	}{
+		{"", "src/a.py", "src/a.py"},
+		{".", "./src/a.py", "src/a.py"},
+		{"repo", "repo/src/a.py", "src/a.py"},
+		{"./repo/", "repo/src/a.py", "src/a.py"},
+		{"/home/me/repo", "/home/me/repo/src/a.py", "src/a.py"},
+		{`C:\repo`, `C:\repo\src\a.py`, "src/a.py"},
+		{"/", "/src/a.py", "src/a.py"},
+		{"repo", "repository/a.py", "repository/a.py"},
+		{"repo", "other/a.py", "other/a.py"},
+	}
+	for _, tt := range tests {
+		if got := relativePath(tt.root, tt.filename); got != tt.want {
+			t.Errorf("relativePath(%q, %q) = %q, want %q", tt.root, tt.filename, got, tt.want)
+		}
+	}
+}
+
+// scanAt analyzes files as if they were found below root.
+func scanAt(root string, files map[string]string) WorkspaceAnalysisResult {
+	req := WorkspaceAnalyzeRequest{Root: root}
+	for name, content := range files {
+		if root != "" {
+			name = root + "/" + name
+		}
+		req.Files = append(req.Files, AnalyzeFile{Filename: name, Content: content})
+	}
+	return New().AnalyzeWorkspace(req)
+}
+
+// symbols lists the symbols of every finding of result, sorted.
+func symbols(result WorkspaceAnalysisResult) []string {
+	var out []string
+	for _, res := range result.Results {
+		for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters, res.Exports, res.Files} {
+			for _, issue := range issues {
+				out = append(out, issue.Symbol)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"app.py":      "import os\nimport sys\n\ndef main(argv):\n    print(sys.path)\n",
+		"lib/util.rb": "def helper(x, y)\n  x\nend\n",
+	}
+	b := NewBaseline(scanAt("", files), "")
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other := NewBaseline(scanAt("/home/ci/checkout", files), "/home/ci/checkout"); !reflect.DeepEqual(other, b) {
+		t.Errorf("baseline taken below another root is %+v, want %+v", other.Issues, b.Issues)
+	}
+
+	edited := map[string]string{
+		// One finding is fixed, one is new and one moves down a line.
+		"app.py":      "import json\nimport sys\n\n\ndef main(argv):\n    print(sys.path)\n",
+		"lib/util.rb": files["lib/util.rb"],
+	}
+	tests := []struct {
+		name  string
+		root  string
+		files map[string]string
+		left  []string
+		fixed []string
+	}{
+		{"same root", "", files, nil, nil},
+		{"relative root", "checkout", files, nil, nil},
+		{"absolute root", "/home/ci/checkout", files, nil, nil},
+		{"edited", "/home/ci/checkout", edited, []string{"json"}, []string{"os"}},
+	}
+	for _, tt := range tests {
+		parsed, err := ParseBaseline(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filtered, fixed := parsed.Filter(scanAt(tt.root, tt.files))
+		if got := symbols(filtered); !reflect.DeepEqual(got, tt.left) {
+			t.Errorf("%s: findings %v left after the baseline, want %v", tt.name, got, tt.left)
+		}
+		var gotFixed []string
+		for _, issue := range fixed {
+			gotFixed = append(gotFixed, issue.Symbol)
+		}
+		if !reflect.DeepEqual(gotFixed, tt.fixed) {
+			t.Errorf("%s: fixed %v, want %v", tt.name, gotFixed, tt.fixed)
+		}
+	}
+}
+
+func TestParseBaselineErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{"version": 1, "issues": []}`, ""},
+		{`{"version": 2, "issues": []}`, "unsupported baseline version 2"},
+		{`[]`, "invalid baseline"},
+	}
+	for _, tt := range tests {
+		_, err := ParseBaseline([]byte(tt.data))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("ParseBaseline(%s) = %v, want no error", tt.data, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("ParseBaseline(%s) = %v, want an error containing %q", tt.data, err, tt.err)
+		}
+	}
+}
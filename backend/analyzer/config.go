@@ -0,0 +1,277 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ConfigFileName is the project configuration file the command line looks
+// for in the scanned directory and its parents.
+const ConfigFileName = ".unusedrc.json"
+
+// Config is the project configuration, usually read from ConfigFileName.
+// The rules of a file are the top-level ones, then those of Languages for
+// its language, then those of every matching override in order, later
+// ones winning; IgnoreNames accumulate instead. The zero value reports
+// everything but unused exports and unreachable files.
+//
+// Path patterns use "/" as separator and support "*", "?" and "**" for
+// any number of directories. They match the end of a filename relative to
+// the workspace Root, so "legacy/**" matches below any legacy directory of
+// the workspace but not the workspace itself when it is one.
+type Config struct {
+	RuleSet
+	// IgnorePaths lists files that get no findings. They are still
+	// analyzed, so references from them keep other code used.
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
+	// EntryPoints lists files whose exported definitions are used from
+	// outside the workspace, such as executables or framework entry
+	// modules. Those are not reported, see Definition.Exported; the other
+	// definitions of an entry point are. Entry points are the roots of the
+	// import graph unreachable files are found with.
+	EntryPoints []string             `json:"entryPoints,omitempty"`
+	Languages   map[Language]RuleSet `json:"languages,omitempty"`
+	Overrides   []ConfigOverride     `json:"overrides,omitempty"`
+}
+
+// RuleSet enables or disables the kinds of findings and names to ignore. A
+// nil switch leaves the inherited setting unchanged.
+type RuleSet struct {
+	Imports    *bool `json:"imports,omitempty"`
+	Variables  *bool `json:"variables,omitempty"`
+	Parameters *bool `json:"parameters,omitempty"`
+	// Exports enables the report of exported definitions no other file
+	// uses, which is off by default.
+	Exports *bool `json:"exports,omitempty"`
+	// DeadFiles enables the report of files no entry point reaches
+	// through imports, which is off by default.
+	DeadFiles *bool `json:"deadFiles,omitempty"`
+	// IgnoreNames are patterns for symbol names that are never reported,
+	// e.g. "_*" or "Test*".
+	IgnoreNames []string `json:"ignoreNames,omitempty"`
+}
+
+// ConfigOverride applies rules to the files matching one of Files.
+type ConfigOverride struct {
+	Files []string `json:"files"`
+	RuleSet
+	Languages map[Language]RuleSet `json:"languages,omitempty"`
+}
+
+// fileRules are the rules resolved for a single file.
+type fileRules struct {
+	imports     bool
+	variables   bool
+	parameters  bool
+	exports     bool
+	deadFiles   bool
+	ignored     bool
+	entryPoint  bool
+	ignoreNames []string
+}
+
+// ParseConfig decodes a JSON configuration and checks its patterns and
+// languages. Unknown keys are rejected, so that a misspelled rule does not
+// go unnoticed.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid config: unexpected data after the configuration")
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	check := func(patterns []string) error {
+		for _, p := range patterns {
+			if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+				return fmt.Errorf("invalid config pattern %q: %w", p, err)
+			}
+		}
+		return nil
+	}
+	checkRules := func(rs RuleSet, langs map[Language]RuleSet) error {
+		if err := check(rs.IgnoreNames); err != nil {
+			return err
+		}
+		for lang, lrs := range langs {
+			if _, ok := LookupLanguage(lang); !ok {
+				return fmt.Errorf("invalid config: unknown language %q", lang)
+			}
+			if err := check(lrs.IgnoreNames); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check(c.IgnorePaths); err != nil {
+		return err
+	}
+	if err := check(c.EntryPoints); err != nil {
+		return err
+	}
+	if err := checkRules(c.RuleSet, c.Languages); err != nil {
+		return err
+	}
+	for _, o := range c.Overrides {
+		if err := check(o.Files); err != nil {
+			return err
+		}
+		if err := checkRules(o.RuleSet, o.Languages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rulesFor resolves the rules for filename of the workspace in directory
+// root. Patterns are matched against the path relative to root, so the
+// directories above it make no difference. A nil Config yields the
+// defaults.
+func (c *Config) rulesFor(root, filename string, lang Language) fileRules {
+	rules := fileRules{imports: true, variables: true, parameters: true}
+	if c == nil {
+		return rules
+	}
+
+	filename = relativePath(root, filename)
+	rules.ignored = matchAnyPath(c.IgnorePaths, filename)
+	rules.entryPoint = matchAnyPath(c.EntryPoints, filename)
+	rules.apply(c.RuleSet)
+	rules.apply(c.Languages[lang])
+	for _, o := range c.Overrides {
+		if matchAnyPath(o.Files, filename) {
+			rules.apply(o.RuleSet)
+			rules.apply(o.Languages[lang])
+		}
+	}
+	return rules
+}
+
+func (r *fileRules) apply(rs RuleSet) {
+	if rs.Imports != nil {
+		r.imports = *rs.Imports
+	}
+	if rs.Variables != nil {
+		r.variables = *rs.Variables
+	}
+	if rs.Parameters != nil {
+		r.parameters = *rs.Parameters
+	}
+	if rs.Exports != nil {
+		r.exports = *rs.Exports
+	}
+	if rs.DeadFiles != nil {
+		r.deadFiles = *rs.DeadFiles
+	}
+	r.ignoreNames = append(r.ignoreNames, rs.IgnoreNames...)
+}
+
+// mayEnable reports whether the rules of any file may turn on the switch
+// of RuleSet that rule returns, for the findings that are off by default
+// and need workspace-wide work, such as resolving exports.
+func (c *Config) mayEnable(rule func(RuleSet) *bool) bool {
+	if c == nil {
+		return false
+	}
+	enabled := func(rs RuleSet, langs map[Language]RuleSet) bool {
+		if on := rule(rs); on != nil && *on {
+			return true
+		}
+		for _, lrs := range langs {
+			if on := rule(lrs); on != nil && *on {
+				return true
+			}
+		}
+		return false
+	}
+	if enabled(c.RuleSet, c.Languages) {
+		return true
+	}
+	for _, o := range c.Overrides {
+		if enabled(o.RuleSet, o.Languages) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoresName matches name against IgnoreNames. Members such as Go's
+// "T.Method" also match by their own name.
+func (r fileRules) ignoresName(name string) bool {
+	member := name[strings.LastIndex(name, ".")+1:]
+	for _, pattern := range r.ignoreNames {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, member); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// configKey identifies a configuration for caching.
+func configKey(c *Config) string {
+	if c == nil {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+func matchAnyPath(patterns []string, filename string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, filename) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether pattern matches filename or any of its trailing
+// sub-paths.
+func matchPath(pattern, filename string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	nameParts := strings.Split(strings.Trim(slashPath(filename), "/"), "/")
+	for i := range nameParts {
+		if matchSegments(patternParts, nameParts[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
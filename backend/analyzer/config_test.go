@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		filename string
+		want     bool
+	}{
+		{"main.py", "main.py", true},
+		{"main.py", "src/app/main.py", true},
+		{"main.py", "src/app/main.pyc", false},
+		{"main.py", "domain.py", false},
+		{"*.py", "src/app/util.py", true},
+		{"*.py", "src/app/util.go", false},
+		{"util?.py", "util1.py", true},
+		{"util?.py", "util12.py", false},
+		{"cmd/**", "cmd/app/main.go", true},
+		{"cmd/**", "tools/cmd/app/main.go", true},
+		{"cmd/**", "cmdline/main.go", false},
+		{"legacy/**/*.php", "src/legacy/a/b/c.php", true},
+		{"legacy/**/*.php", "src/legacy/c.php", true},
+		{"legacy/**/*.php", "src/legacy/c.rb", false},
+		{"/generated/", "x/generated", true},
+		{"tests/*.py", `tests\test_a.py`, true},
+		{"src/*.py", "src/app/util.py", false},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.filename); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestRulesFor(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+		"ignoreNames": ["_*"],
+		"ignorePaths": ["generated/**"],
+		"entryPoints": ["cmd/**", "main.py"],
+		"exports": true,
+		"languages": {"python": {"parameters": false, "ignoreNames": ["test_*"]}},
+		"overrides": [
+			{"files": ["tests/**"], "variables": false, "languages": {"go": {"imports": false}}},
+			{"files": ["tests/keep/**"], "variables": true}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filename string
+		lang     Language
+		want     fileRules
+	}{
+		{"lib/a.go", LangGo, fileRules{imports: true, variables: true, parameters: true, exports: true, ignoreNames: []string{"_*"}}},
+		{"lib/a.py", LangPython, fileRules{imports: true, variables: true, exports: true, ignoreNames: []string{"_*", "test_*"}}},
+		{"main.py", LangPython, fileRules{imports: true, variables: true, exports: true, entryPoint: true, ignoreNames: []string{"_*", "test_*"}}},
+		{"cmd/app/main.go", LangGo, fileRules{imports: true, variables: true, parameters: true, exports: true, entryPoint: true, ignoreNames: []string{"_*"}}},
+		{"generated/a/b.go", LangGo, fileRules{imports: true, variables: true, parameters: true, exports: true, ignored: true, ignoreNames: []string{"_*"}}},
+		{"tests/a.go", LangGo, fileRules{parameters: true, exports: true, ignoreNames: []string{"_*"}}},
+		{"tests/a.rb", LangRuby, fileRules{imports: true, parameters: true, exports: true, ignoreNames: []string{"_*"}}},
+		{"tests/keep/a.go", LangGo, fileRules{variables: true, parameters: true, exports: true, ignoreNames: []string{"_*"}}},
+	}
+	for _, tt := range tests {
+		if got := cfg.rulesFor("", tt.filename, tt.lang); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("rulesFor(%q, %s) = %+v, want %+v", tt.filename, tt.lang, got, tt.want)
+		}
+	}
+
+	var none *Config
+	want := fileRules{imports: true, variables: true, parameters: true}
+	if got := none.rulesFor("", "a.py", LangPython); !reflect.DeepEqual(got, want) {
+		t.Errorf("nil Config rulesFor = %+v, want %+v", got, want)
+	}
+}
+
+func TestRulesForMatchesBelowRoot(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+		"ignorePaths": ["build/**"],
+		"entryPoints": ["repo/main.py"],
+		"overrides": [{"files": ["tmp/**"], "imports": false}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		root     string
+		filename string
+		ignored  bool
+		entry    bool
+		imports  bool
+	}{
+		{"/tmp/build/repo", "/tmp/build/repo/app.py", false, false, true},
+		{"/tmp/build/repo", "/tmp/build/repo/build/gen.py", true, false, true},
+		{"/tmp/build/repo", "/tmp/build/repo/main.py", false, false, true},
+		{"/tmp/build", "/tmp/build/repo/main.py", false, true, true},
+		{"", "/tmp/build/repo/app.py", true, false, false},
+		{".", "build/app.py", true, false, true},
+	}
+	for _, tt := range tests {
+		got := cfg.rulesFor(tt.root, tt.filename, LangPython)
+		if got.ignored != tt.ignored || got.entryPoint != tt.entry || got.imports != tt.imports {
+			t.Errorf("rulesFor(%q, %q) = ignored %v, entry point %v, imports %v; want %v, %v, %v",
+				tt.root, tt.filename, got.ignored, got.entryPoint, got.imports, tt.ignored, tt.entry, tt.imports)
+		}
+	}
+
+	files := []AnalyzeFile{{Filename: "/tmp/build/repo/app.py", Content: "import os\n"}}
+	res := New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: files, Root: "/tmp/build/repo", Config: cfg})
+	if got := res.Results["/tmp/build/repo/app.py"].Imports; len(got) != 1 {
+		t.Errorf("workspace below a build directory reports %v, want the unused import", got)
+	}
+}
+
+func TestIgnoresName(t *testing.T) {
+	rules := fileRules{ignoreNames: []string{"_*", "Test*"}}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"_private", true},
+		{"public", false},
+		{"TestMain", true},
+		{"Server._close", true},
+		{"Server.Close", false},
+	}
+	for _, tt := range tests {
+		if got := rules.ignoresName(tt.name); got != tt.want {
+			t.Errorf("ignoresName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		err    string // "" for a valid config
+	}{
+		{`{}`, ""},
+		{`{"imports": false, "deadFiles": true, "languages": {"vue": {"variables": false}}}`, ""},
+		{`{"ignorePath": ["x"]}`, `unknown field "ignorePath"`},
+		{`{"languages": {"python": {"variabels": false}}}`, `unknown field "variabels"`},
+		{`{"overrides": [{"files": ["a"], "exprots": true}]}`, `unknown field "exprots"`},
+		{`{"languages": {"pyton": {}}}`, `unknown language "pyton"`},
+		{`{"overrides": [{"files": ["a"], "languages": {"rust": {}}}]}`, `unknown language "rust"`},
+		{`{"ignoreNames": ["[a"]}`, `invalid config pattern "[a"`},
+		{`{"imports": false} {}`, "unexpected data"},
+		{`{"imports": "no"}`, "invalid config"},
+	}
+	for _, tt := range tests {
+		_, err := ParseConfig([]byte(tt.config))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("ParseConfig(%s) = %v, want no error", tt.config, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("ParseConfig(%s) = %v, want an error containing %q", tt.config, err, tt.err)
+		}
+	}
+}
+
+func TestEntryPointReportsOnlyLocalDefinitions(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"entryPoints": ["main.py"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	la, _ := LookupLanguage(LangPython)
+	content := "def handler():\n    pass\n\ndef _helper():\n    pass\n"
+	for _, tt := range []struct {
+		filename string
+		want     []string
+	}{
+		{"main.py", []string{"_helper"}},
+		{"lib.py", []string{"handler", "_helper"}},
+	} {
+		file := AnalyzeFile{Filename: tt.filename, Content: content}
+		res := BuildAnalysisResult(la, file, la.Parse(content, tt.filename), nil, cfg)
+		var got []string
+		for _, issue := range res.Variables {
+			got = append(got, issue.Symbol)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: unused variables %v, want %v", tt.filename, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package analyzer
 
 const (
 	LangTypeScript Language = "typescript"
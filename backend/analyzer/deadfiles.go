@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// FileIndex looks up the files of a workspace by path for ImportResolver.
+// Paths use "/" as separator; the filenames returned are those of the
+// workspace, ordered by name.
+type FileIndex struct {
+	files map[string]AnalyzeFile // cleaned path to file
+	dirs  map[string][]string    // cleaned directory to filenames
+	bases map[string][]string    // base name to filenames
+}
+
+// NewFileIndex indexes files.
+func NewFileIndex(files []AnalyzeFile) *FileIndex {
+	ix := &FileIndex{
+		files: make(map[string]AnalyzeFile, len(files)),
+		dirs:  make(map[string][]string),
+		bases: make(map[string][]string),
+	}
+	for _, f := range files {
+		p := indexPath(f.Filename)
+		ix.files[p] = f
+		ix.dirs[path.Dir(p)] = append(ix.dirs[path.Dir(p)], f.Filename)
+		ix.bases[path.Base(p)] = append(ix.bases[path.Base(p)], f.Filename)
+	}
+	for _, names := range ix.dirs {
+		sort.Strings(names)
+	}
+	for _, names := range ix.bases {
+		sort.Strings(names)
+	}
+	return ix
+}
+
+func indexPath(filename string) string {
+	return path.Clean(slashPath(filename))
+}
+
+// File returns the filename of the file at p, which is cleaned first.
+func (ix *FileIndex) File(p string) (string, bool) {
+	f, ok := ix.files[path.Clean(p)]
+	return f.Filename, ok
+}
+
+// Content returns the content of the file at p, such as a go.mod a
+// resolver reads.
+func (ix *FileIndex) Content(p string) string {
+	return ix.files[path.Clean(p)].Content
+}
+
+// Dir returns the files directly in directory dir.
+func (ix *FileIndex) Dir(dir string) []string {
+	return ix.dirs[path.Clean(dir)]
+}
+
+// Find returns the files whose path is suffix or ends with "/" and suffix,
+// such as "pkg/mod.py" for a module imported without knowing the root it
+// is relative to.
+func (ix *FileIndex) Find(suffix string) []string {
+	suffix = path.Clean(suffix)
+	var out []string
+	for _, filename := range ix.bases[path.Base(suffix)] {
+		if p := indexPath(filename); p == suffix || strings.HasSuffix(p, "/"+suffix) {
+			out = append(out, filename)
+		}
+	}
+	return out
+}
+
+// FindDirs returns the directories holding files whose path is suffix or
+// ends with "/" and suffix.
+func (ix *FileIndex) FindDirs(suffix string) []string {
+	suffix = path.Clean(suffix)
+	var out []string
+	for dir := range ix.dirs {
+		if dir == suffix || strings.HasSuffix(dir, "/"+suffix) {
+			out = append(out, dir)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// unreachableFiles returns the files of a language that no entry point or
+// test file reaches through imports resolved by ImportResolver, or nil
+// when the workspace has no configured entry point. Only imports of files
+// in the workspace count, so files loaded by convention or from another
+// language, such as Astro pages or a Vue app mounted from main.ts, must be
+// entry points themselves.
+func (ws *workspace) unreachableFiles(files []AnalyzeFile) map[string]bool {
+	ix := NewFileIndex(files)
+	deps := make([][]string, len(files))
+	forEach(len(files), func(i int) {
+		if la := languageFor(files[i].Filename); la != nil {
+			if resolver, ok := la.(ImportResolver); ok {
+				deps[i] = resolver.ResolveImports(files[i], ws.parsed[files[i].Filename], ix)
+			}
+		}
+	})
+
+	graph := make(map[string][]string, len(files))
+	var queue []string
+	reached := make(map[string]bool)
+	configured := false
+	for i, f := range files {
+		la := languageFor(f.Filename)
+		if la == nil {
+			continue
+		}
+		graph[f.Filename] = deps[i]
+		entryPoint := ws.config.rulesFor(ws.root, f.Filename, la.Language()).entryPoint
+		configured = configured || entryPoint
+		if tests, ok := la.(TestFileDetector); entryPoint || ok && tests.IsTestFile(f.Filename) {
+			reached[f.Filename] = true
+			queue = append(queue, f.Filename)
+		}
+	}
+	if !configured {
+		return nil
+	}
+	for len(queue) > 0 {
+		filename := queue[0]
+		queue = queue[1:]
+		for _, dep := range graph[filename] {
+			if !reached[dep] {
+				reached[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	unreachable := make(map[string]bool)
+	for filename := range graph {
+		if !reached[filename] {
+			unreachable[filename] = true
+		}
+	}
+	return unreachable
+}
+
+// unreachableFileIssues returns the finding for file when it is
+// unreachable and the rules report it.
+func unreachableFileIssues(la LanguageAnalyzer, file AnalyzeFile, cfg *Config, root string, unreachable bool) []CodeIssue {
+	issues := []CodeIssue{}
+	rules := cfg.rulesFor(root, file.Filename, la.Language())
+	if !unreachable || !rules.deadFiles || rules.ignored || rules.entryPoint {
+		return issues
+	}
+	if parseSuppressions(file.Content, la.Comments()).suppressed(1, "file") {
+		return issues
+	}
+
+	ids := newIssueBuilder(la, file, root)
+	first := strings.TrimRight(ids.lines[0], " \t")
+	base := path.Base(slashPath(file.Filename))
+	at := CodeIssue{
+		Line:      1,
+		Column:    1,
+		EndLine:   1,
+		EndColumn: utf16Column(first, len(first)),
+		Text:      "file " + base,
+		Kind:      "file",
+		Symbol:    base,
+	}
+	if issue, ok := ids.issue("file", at); ok {
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// findWithExtensions returns the files Find finds for p or, when there
+// are none, for p with the first of exts appended or an index file with it
+// that has any.
+func findWithExtensions(ix *FileIndex, p string, exts []string) []string {
+	if found := ix.Find(p); len(found) > 0 {
+		return found
+	}
+	for _, candidate := range []string{p, p + "/index"} {
+		for _, ext := range exts {
+			if found := ix.Find(candidate + ext); len(found) > 0 {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// resolveRelative returns the files a relative import source names from
+// the directory dir: the path itself or, when it has no matching file,
+// the path with one of exts appended, or an index file with one of them.
+func resolveRelative(ix *FileIndex, dir, source string, exts []string) []string {
+	p := path.Join(dir, source)
+	if filename, ok := ix.File(p); ok {
+		return []string{filename}
+	}
+	for _, candidate := range []string{p, p + "/index"} {
+		for _, ext := range exts {
+			if filename, ok := ix.File(candidate + ext); ok {
+				return []string{filename}
+			}
+		}
+	}
+	return nil
+}
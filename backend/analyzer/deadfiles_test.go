@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// deadFiles returns the files reported as unreachable in files, sorted.
+func deadFiles(t *testing.T, files map[string]string, config string) []string {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := []string{}
+	for filename, res := range New().AnalyzeWorkspace(workspaceRequest(files, cfg)).Results {
+		if len(res.Files) > 0 {
+			dead = append(dead, filename)
+		}
+	}
+	sort.Strings(dead)
+	return dead
+}
+
+func TestDeadFilesReachedFromEntryPoints(t *testing.T) {
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.22\n",
+		"main.go":          "package main\n\nimport \"example.com/app/util\"\n\nfunc main() { util.Run() }\n",
+		"util/util.go":     "package util\n\nfunc Run() {}\n",
+		"util/extra.go":    "package util\n\nfunc Extra() {}\n",
+		"orphan/orphan.go": "package orphan\n\nfunc Orphan() {}\n",
+		"app.py":           "from pkg import mod\n\nmod.run()\n",
+		"pkg/__init__.py":  "",
+		"pkg/mod.py":       "from . import helpers\n\ndef run():\n    helpers.go()\n",
+		"pkg/helpers.py":   "def go():\n    pass\n",
+		"pkg/old.py":       "def old():\n    pass\n",
+		"lib/task.rb":      "require_relative 'step'\n",
+		"lib/step.rb":      "",
+		"lib/stale.rb":     "",
+	}
+	tests := []struct {
+		config string
+		want   []string
+	}{
+		{`{"deadFiles": true}`, []string{}},
+		{`{"deadFiles": true, "entryPoints": ["main.go", "app.py", "lib/task.rb"]}`, []string{"lib/stale.rb", "orphan/orphan.go", "pkg/old.py"}},
+		{`{"deadFiles": true, "entryPoints": ["main.go"]}`, []string{"app.py", "lib/stale.rb", "lib/step.rb", "lib/task.rb", "orphan/orphan.go", "pkg/__init__.py", "pkg/helpers.py", "pkg/mod.py", "pkg/old.py"}},
+		{`{"entryPoints": ["main.go"]}`, []string{}},
+	}
+	for _, tt := range tests {
+		if got := deadFiles(t, files, tt.config); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("config %s: dead files %v, want %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestDeadFilesTestFilesAreEntryPoints(t *testing.T) {
+	files := map[string]string{
+		"go.mod":                 "module example.com/app\n\ngo 1.22\n",
+		"main.go":                "package main\n\nfunc main() {}\n",
+		"main_test.go":           "package main\n\nimport (\n\t\"testing\"\n\n\t\"example.com/app/fixture\"\n)\n\nfunc TestMain(t *testing.T) { fixture.Load() }\n",
+		"fixture/fixture.go":     "package fixture\n\nfunc Load() {}\n",
+		"orphan/orphan.go":       "package orphan\n\nfunc Orphan() {}\n",
+		"orphan/orphan_test.go":  "package orphan\n",
+		"app.py":                 "print(1)\n",
+		"tests/test_app.py":      "from testing_helpers import setup\n",
+		"tests/app_test.py":      "",
+		"testing_helpers.py":     "def setup():\n    pass\n",
+		"helpers_unused.py":      "",
+		"spec/models_spec.rb":    "require_relative '../lib/factory'\n",
+		"lib/factory.rb":         "",
+		"test/test_job.rb":       "",
+		"lib/job_test.rb":        "",
+		"tests/ServiceTest.php":  "<?php\n",
+		"src/Tested.php":         "<?php\n",
+		"web/Button.spec.svelte": "<script>\n  import Button from './Button.svelte';\n</script>\n",
+		"web/Button.svelte":      "<button>ok</button>\n",
+		"web/Card.test.vue":      "<template><div /></template>\n",
+		"web/Orphan.vue":         "<template><div /></template>\n",
+	}
+	got := deadFiles(t, files, `{"deadFiles": true, "entryPoints": ["main.go", "app.py"]}`)
+	// A test file reaches what it imports and, in Go, its own package.
+	want := []string{"helpers_unused.py", "src/Tested.php", "web/Orphan.vue"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dead files %v, want %v", got, want)
+	}
+
+	// Tests alone are no configured entry point.
+	if got := deadFiles(t, files, `{"deadFiles": true}`); len(got) != 0 {
+		t.Errorf("without entry points: dead files %v, want none", got)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := map[string]bool{
+		"pkg/x_test.go":      true,
+		"pkg/x.go":           false,
+		"pkg/testing.go":     false,
+		"tests/test_x.py":    true,
+		"x_test.py":          true,
+		"tests/conftest.py":  false,
+		"testing.py":         false,
+		"spec/x_spec.rb":     true,
+		"test/x_test.rb":     true,
+		"test/test_x.rb":     true,
+		"lib/spec_helper.rb": false,
+		"tests/XTest.php":    true,
+		"src/Test.php":       true,
+		"src/Testing.php":    false,
+		"src/X.test.svelte":  true,
+		"src/X.spec.vue":     true,
+		"src/latest.vue":     false,
+		"src/X.spec/Y.astro": false,
+	}
+	for filename, want := range tests {
+		detector, ok := languageFor(filename).(TestFileDetector)
+		if !ok {
+			t.Errorf("%s: language has no TestFileDetector", filename)
+			continue
+		}
+		if got := detector.IsTestFile(filename); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", filename, got, want)
+		}
+	}
+}
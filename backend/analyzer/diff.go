@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ChangedLines holds the lines a unified diff adds or modifies, keyed by
+// the filename on the new side of the diff.
+type ChangedLines map[string]map[int]bool
+
+// ParseUnifiedDiff reads the output of diff -u or git diff. The "b/" prefix
+// git puts before filenames is removed. Deleted files, removed lines and
+// anything that is no file header or hunk, such as git's extended headers,
+// are ignored, and so are malformed hunks.
+func ParseUnifiedDiff(diff string) ChangedLines {
+	changed := make(ChangedLines)
+	var lines map[int]bool
+	// line is the next line on the new side; oldLeft and newLeft count the
+	// lines of the current hunk still to come, so that a removed "-- x" or
+	// an added "++ x" is not taken for a file header.
+	line, oldLeft, newLeft := 0, 0, 0
+	for _, text := range strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if lines != nil {
+					lines[line] = true
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+			default:
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			lines = nil
+			if name := diffFilename(text[len("+++ "):]); name != "" {
+				if changed[name] == nil {
+					changed[name] = make(map[int]bool)
+				}
+				lines = changed[name]
+			}
+		case strings.HasPrefix(text, "@@ "):
+			line, oldLeft, newLeft = parseHunkHeader(text)
+		}
+	}
+	return changed
+}
+
+// diffFilename extracts the filename of a "+++" header, or "" for
+// /dev/null.
+func diffFilename(header string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		header = unquoted
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, "b/")
+}
+
+// parseHunkHeader returns the first new line and the old and new line
+// counts of a hunk header such as "@@ -1,4 +1,5 @@". A malformed header
+// yields zeros, which skips the hunk.
+func parseHunkHeader(header string) (start, oldCount, newCount int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0
+	}
+	_, oldCount, ok := parseRange(fields[1][1:])
+	if !ok {
+		return 0, 0, 0
+	}
+	start, newCount, ok = parseRange(fields[2][1:])
+	if !ok {
+		return 0, 0, 0
+	}
+	return start, oldCount, newCount
+}
+
+// parseRange parses "start,count" or "start", which has a count of one.
+func parseRange(r string) (start, count int, ok bool) {
+	first, rest, hasCount := strings.Cut(r, ",")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// Filter returns result, analyzed with root as WorkspaceAnalyzeRequest.Root,
+// with only the findings declared on changed lines. Diff filenames are
+// relative to root, as "git diff --relative" run there writes them. Usage
+// is still decided by the whole workspace.
+func (c ChangedLines) Filter(result WorkspaceAnalysisResult, root string) WorkspaceAnalysisResult {
+	changed := make(ChangedLines, len(c))
+	for name, lines := range c {
+		changed[relativePath("", name)] = lines
+	}
+	filtered := WorkspaceAnalysisResult{Results: make(map[string]AnalysisResult, len(result.Results))}
+	for filename, res := range result.Results {
+		lines := changed[relativePath(root, filename)]
+		keep := func(issues []CodeIssue) []CodeIssue {
+			out := []CodeIssue{}
+			for _, issue := range issues {
+				if lines[issue.Line] {
+					out = append(out, issue)
+				}
+			}
+			return out
+		}
+		filtered.Results[filename] = AnalysisResult{
+			Imports:    keep(res.Imports),
+			Variables:  keep(res.Variables),
+			Parameters: keep(res.Parameters),
+			Exports:    keep(res.Exports),
+			Files:      keep(res.Files),
+		}
+	}
+	return filtered
+}
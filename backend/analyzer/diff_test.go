@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want map[string][]int
+	}{
+		{
+			name: "git diff",
+			diff: "diff --git a/app.py b/app.py\nindex 1111111..2222222 100644\n--- a/app.py\n+++ b/app.py\n@@ -1,3 +1,4 @@\n import os\n-import sys\n+import json\n+import re\n def main():\n",
+			want: map[string][]int{"app.py": {2, 3}},
+		},
+		{
+			name: "diff -u with timestamps",
+			diff: "--- old/lib.go\t2026-01-01 00:00:00\n+++ new/lib.go\t2026-01-02 00:00:00\n@@ -10,2 +10,3 @@\n a\n+b\n c\n",
+			want: map[string][]int{"new/lib.go": {11}},
+		},
+		{
+			name: "multiple hunks and files",
+			diff: "--- a/a.py\n+++ b/a.py\n@@ -1 +1 @@\n-x = 1\n+x = 2\n@@ -20,3 +20,3 @@\n a\n-b\n+c\n d\n--- a/b.rb\n+++ b/b.rb\n@@ -5,0 +6,2 @@\n+one\n+two\n",
+			want: map[string][]int{"a.py": {1, 21}, "b.rb": {6, 7}},
+		},
+		{
+			name: "new file",
+			diff: "--- /dev/null\n+++ b/new.py\n@@ -0,0 +1,2 @@\n+import os\n+x = 1\n",
+			want: map[string][]int{"new.py": {1, 2}},
+		},
+		{
+			name: "deleted file",
+			diff: "--- a/gone.py\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-import os\n-x = 1\n",
+			want: map[string][]int{},
+		},
+		{
+			name: "header-like lines inside a hunk",
+			diff: "--- a/a.sql\n+++ b/a.sql\n@@ -1,2 +1,2 @@\n--- old comment\n+++ new comment\n keep\n",
+			want: map[string][]int{"a.sql": {1}},
+		},
+		{
+			name: "no newline at end of file",
+			diff: "--- a/a.py\n+++ b/a.py\n@@ -1 +1,2 @@\n-x\n\\ No newline at end of file\n+x\n+y\n\\ No newline at end of file\n",
+			want: map[string][]int{"a.py": {1, 2}},
+		},
+		{
+			name: "quoted filename",
+			diff: "--- \"a/my file.py\"\n+++ \"b/my file.py\"\n@@ -1 +1 @@\n-a\n+b\n",
+			want: map[string][]int{"my file.py": {1}},
+		},
+		{
+			name: "malformed hunk header",
+			diff: "--- a/a.py\n+++ b/a.py\n@@ -x +y @@\n+skipped\n@@ -3 +3 @@\n-a\n+b\n",
+			want: map[string][]int{"a.py": {3}},
+		},
+		{
+			name: "CRLF line endings",
+			diff: "--- a/a.py\r\n+++ b/a.py\r\n@@ -1 +1 @@\r\n-a\r\n+b\r\n",
+			want: map[string][]int{"a.py": {1}},
+		},
+	}
+	for _, tt := range tests {
+		got := ParseUnifiedDiff(tt.diff)
+		want := make(ChangedLines, len(tt.want))
+		for name, lines := range tt.want {
+			want[name] = make(map[int]bool, len(lines))
+			for _, line := range lines {
+				want[name][line] = true
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ParseUnifiedDiff = %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestChangedLinesFilter(t *testing.T) {
+	changed := ChangedLines{"src/app.py": {3: true}, "./lib.rb": {1: true}, "a.py": {3: true}}
+	issue := func(line int) CodeIssue { return CodeIssue{Line: line} }
+	result := WorkspaceAnalysisResult{Results: map[string]AnalysisResult{
+		"/repo/src/app.py": {Imports: []CodeIssue{issue(1), issue(3)}, Variables: []CodeIssue{issue(3)}},
+		"/repo/lib.rb":     {Parameters: []CodeIssue{issue(1), issue(2)}, Files: []CodeIssue{issue(1)}},
+		"/repo/other.py":   {Imports: []CodeIssue{issue(3)}, Exports: []CodeIssue{issue(3)}},
+		"/repo/myapp.py":   {Imports: []CodeIssue{issue(3)}},
+		"/repo/pkg/a.py":   {Imports: []CodeIssue{issue(3)}},
+		"/repo/a.py":       {Imports: []CodeIssue{issue(3)}},
+	}}
+
+	tests := []struct {
+		filename string
+		want     []int // lines of all issues kept, in field order
+	}{
+		{"/repo/src/app.py", []int{3, 3}},
+		{"/repo/lib.rb", []int{1, 1}},
+		{"/repo/other.py", nil},
+		{"/repo/myapp.py", nil},
+		{"/repo/pkg/a.py", nil},
+		{"/repo/a.py", []int{3}},
+	}
+	filtered := changed.Filter(result, "/repo")
+	for _, tt := range tests {
+		res, ok := filtered.Results[tt.filename]
+		if !ok {
+			t.Errorf("Filter dropped the result of %s", tt.filename)
+			continue
+		}
+		var got []int
+		for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters, res.Exports, res.Files} {
+			for _, issue := range issues {
+				got = append(got, issue.Line)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Filter kept lines %v of %s, want %v", got, tt.filename, tt.want)
+		}
+	}
+
+	// Below another root the same diff matches other files.
+	filtered = changed.Filter(result, "/repo/src")
+	if got := filtered.Results["/repo/src/app.py"].Imports; len(got) != 0 {
+		t.Errorf("Filter below /repo/src kept %v of app.py, want none", got)
+	}
+}
@@ -0,0 +1,281 @@
+package analyzer
+
+import "strings"
+
+// Rules of a Reference, telling which check counted it.
+const (
+	// RuleLocal is a reference in the declaring file.
+	RuleLocal = "local"
+	// RuleTemplate is a use in the markup of an Astro, Svelte or Vue
+	// component.
+	RuleTemplate = "template"
+	// RuleCrossFile is a whole-word match in the code of another file.
+	RuleCrossFile = "cross-file"
+	// RuleTypeChecked is an identifier go/types resolves to the
+	// declaration, in the declaring file or another one.
+	RuleTypeChecked = "type-checked"
+	// RuleImplicit is a use without a location, such as a method that
+	// satisfies an interface. Text says what it is.
+	RuleImplicit = "implicit"
+)
+
+// ExplainRequest names the declaration to explain: a name Filename
+// imports or declares, as in the Symbol of its findings.
+type ExplainRequest struct {
+	Filename string `json:"filename"`
+	Symbol   string `json:"symbol"`
+}
+
+// Explanation tells why Symbol of File is reported as unused or not.
+// Kind is "import", "parameter" or the type of definition, or "" when
+// File declares no such name in the workspace last analyzed. Used tells
+// whether any check found the name used, and References lists where. A
+// used name is still Reported when it is an unused export. Exemption says
+// why a name that is not used is still not reported.
+type Explanation struct {
+	File       string      `json:"file"`
+	Symbol     string      `json:"symbol"`
+	Kind       string      `json:"kind"`
+	Used       bool        `json:"used"`
+	Reported   bool        `json:"reported"`
+	Exemption  string      `json:"exemption,omitempty"`
+	References []Reference `json:"references"`
+}
+
+// Reference is a place where a check found a name used. Text is the
+// source line, or for RuleImplicit, a description; an implicit reference
+// has no position.
+type Reference struct {
+	File string `json:"file"`
+	Span
+	Rule string `json:"rule"`
+	Text string `json:"text"`
+}
+
+// explainedDecl is the declaration of an explained name.
+type explainedDecl struct {
+	kind     string
+	category string // "import", "variable" or "parameter"
+	exported bool
+	imp      Import
+	line     int
+	column   int
+}
+
+// Explain explains a name of the workspace of the last AnalyzeWorkspace or
+// UpdateFiles call. It repeats the checks BuildAnalysisResult makes for
+// the name and lists the references of those that found it used, located
+// by the language's ReferenceFinder or else by a whole-word search, which
+// is what the usage checks of those languages do.
+func (a *MultiLangAnalyzer) Explain(req ExplainRequest) Explanation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ex := Explanation{File: req.Filename, Symbol: req.Symbol, References: []Reference{}}
+	if a.ws == nil || req.Symbol == "" {
+		return ex
+	}
+	ws := a.ws
+	file, ok := ws.files[req.Filename]
+	la := languageFor(req.Filename)
+	if !ok || la == nil {
+		return ex
+	}
+	parsed := ws.parsed[file.Filename]
+	name := req.Symbol
+	decl, ok := findDeclaration(parsed, name)
+	if !ok {
+		return ex
+	}
+	ex.Kind = decl.kind
+
+	localUsed := la.UsedLocally(file.Content, parsed)
+	_, resolved := la.(WorkspaceResolver)
+	usedElsewhere := func(n string) bool {
+		if fileScoped(la) {
+			return false
+		}
+		if resolved {
+			return ws.resolved[la.Language()][n+"@"+file.Filename]
+		}
+		return ws.usedElsewhere(file.Filename, n)
+	}
+	elsewhere := decl.category != "parameter" && usedElsewhere(name)
+	ex.Used = localUsed[name] || elsewhere
+
+	if finder, ok := la.(ReferenceFinder); ok {
+		ex.References = append(ex.References, finder.FindReferences(ws.sortedFiles(), file, parsed, name)...)
+	} else if localUsed[name] {
+		code := maskCode(file.Content, la.Comments())
+		ex.References = append(ex.References, wordReferences(file, code, name, RuleLocal, decl.declares(code, name))...)
+	}
+	if elsewhere && !resolved {
+		for _, other := range ws.sortedFiles() {
+			if other.Filename == file.Filename || (isIdentifierWord(name) && !ws.refs[name][other.Filename]) {
+				continue
+			}
+			code := ws.parsed[other.Filename].Code
+			if ola := languageFor(other.Filename); ola != nil {
+				if _, ok := ola.(IdentifierTokenizer); ok {
+					code = maskCode(code, ola.Comments())
+				}
+			}
+			ex.References = append(ex.References, wordReferences(other, code, name, RuleCrossFile, nil)...)
+		}
+	}
+
+	ex.Reported = reported(ws.results[file.Filename], decl, name)
+	if !ex.Used && !ex.Reported {
+		ex.Exemption = exemption(la, file, ws.config, ws.root, decl, name, func(n string) bool {
+			return localUsed[n] || usedElsewhere(n)
+		})
+	}
+	return ex
+}
+
+// findDeclaration finds the import, definition or parameter name in
+// parsed, in the order BuildAnalysisResult reports them.
+func findDeclaration(parsed ParsedFile, name string) (explainedDecl, bool) {
+	for _, imp := range parsed.Imports {
+		for _, n := range importNames(imp) {
+			if n == name {
+				return explainedDecl{kind: "import", category: "import", imp: imp, line: imp.Line, column: imp.Column}, true
+			}
+		}
+	}
+	for _, def := range parsed.Definitions {
+		if def.Name == name {
+			return explainedDecl{kind: definitionKind(def), category: "variable", exported: def.Exported, line: def.Line, column: def.Column}, true
+		}
+	}
+	for _, p := range parsed.Parameters {
+		if p.Symbol == name {
+			return explainedDecl{kind: "parameter", category: "parameter", line: p.Line, column: p.Column}, true
+		}
+	}
+	return explainedDecl{}, false
+}
+
+// declares returns a function reporting whether the occurrence of name at
+// line and column of code is the declaration itself: any occurrence in an
+// import statement, or the one at the declared position, which is the
+// first on its line when the parser left the column out.
+func (d explainedDecl) declares(code, name string) func(line, column int) bool {
+	if d.category == "import" {
+		from, to := d.imp.Statement.Line, d.imp.Statement.EndLine
+		if from == 0 {
+			from, to = d.imp.Line, d.imp.EndLine
+		}
+		if to < from {
+			to = from
+		}
+		return func(line, column int) bool { return line >= from && line <= to }
+	}
+
+	declColumn := d.column
+	if lines := strings.Split(code, "\n"); declColumn == 0 && d.line >= 1 && d.line <= len(lines) {
+		if pos := indexWordInLine(lines[d.line-1], name); pos >= 0 {
+			declColumn = utf16Column(lines[d.line-1], pos)
+		}
+	}
+	return func(line, column int) bool { return line == d.line && column == declColumn }
+}
+
+// wordReferences returns the whole-word occurrences of name in code, the
+// content of file with only its line layout kept, for which skip, if not
+// nil, is false.
+func wordReferences(file AnalyzeFile, code, name, rule string, skip func(line, column int) bool) []Reference {
+	var refs []Reference
+	source := strings.Split(file.Content, "\n")
+	for i, text := range strings.Split(code, "\n") {
+		for offset := 0; offset < len(text); {
+			pos := indexWordInLine(text[offset:], name)
+			if pos < 0 {
+				break
+			}
+			pos += offset
+			offset = pos + len(name)
+			column := utf16Column(text, pos)
+			if skip != nil && skip(i+1, column) {
+				continue
+			}
+			ref := Reference{
+				File: file.Filename,
+				Span: Span{Line: i + 1, Column: column, EndLine: i + 1, EndColumn: column + utf16Len(name)},
+				Rule: rule,
+			}
+			if i < len(source) {
+				ref.Text = strings.TrimSpace(source[i])
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// reported reports whether res holds a finding for the declaration,
+// including one as unused export.
+func reported(res AnalysisResult, d explainedDecl, name string) bool {
+	issues := res.Variables
+	switch d.category {
+	case "import":
+		issues = res.Imports
+	case "parameter":
+		issues = res.Parameters
+	}
+	if d.category == "variable" {
+		issues = append(append([]CodeIssue(nil), issues...), res.Exports...)
+	}
+	for _, issue := range issues {
+		if issue.Symbol == name || (d.category == "import" && issue.Line == d.imp.Line && issue.Symbol == d.imp.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// exemption tells why the unused declaration of name is not reported,
+// checking what BuildAnalysisResult checks. isUsed tells whether another
+// name of the file is used.
+func exemption(la LanguageAnalyzer, file AnalyzeFile, cfg *Config, root string, d explainedDecl, name string, isUsed func(string) bool) string {
+	rules := cfg.rulesFor(root, file.Filename, la.Language())
+	sup := parseSuppressions(file.Content, la.Comments())
+	switch {
+	case rules.ignored:
+		return "the file is ignored by the configuration"
+	case d.category == "import" && !rules.imports,
+		d.category == "variable" && !rules.variables,
+		d.category == "parameter" && !rules.parameters:
+		return "the rule is disabled by the configuration"
+	case d.category == "variable" && rules.entryPoint && d.exported:
+		return "the file is an entry point exporting it"
+	case rules.ignoresName(name):
+		return "the name is ignored by the configuration"
+	case d.category == "parameter" && followsUnusedIdiom(la, name):
+		return "the name marks the parameter as intentionally unused"
+	}
+
+	switch d.category {
+	case "import":
+		for _, other := range importNames(d.imp) {
+			if other == name {
+				continue
+			}
+			if isUsed(other) || rules.ignoresName(other) {
+				return "another name of the same import statement is used"
+			}
+		}
+		if sup.suppressed(d.imp.Line, "import") || sup.suppressed(d.imp.Statement.Line, "import") {
+			return "a suppression comment covers it"
+		}
+	case "variable":
+		if sup.suppressed(d.line, "variable", d.kind) {
+			return "a suppression comment covers it"
+		}
+	case "parameter":
+		if sup.suppressed(d.line, "parameter") {
+			return "a suppression comment covers it"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	files := map[string]string{
+		"go.mod":      "module example.com/app\n\ngo 1.22\n",
+		"lib/lib.go":  "package lib\n\ntype Runner interface{ Run() }\n\ntype impl struct{}\n\nfunc (impl) Run() {}\n\nfunc New() Runner { return impl{} }\n\nfunc helper() int { return 1 }\n\nvar Count = helper()\n",
+		"main.go":     "package main\n\nimport \"example.com/app/lib\"\n\nfunc main() {\n\tlib.New().Run()\n\t_ = lib.Count\n}\n",
+		"lib.py":      "import os, sys\nimport json  # unused-ignore\n\ndef shared():\n    return os.sep\n\ndef local():\n    pass\n\nlocal()\n\ndef ignored_helper(_unused, arg):\n    pass\n",
+		"app.py":      "from lib import shared\n\n# shared in a comment\nprint(\"shared\", shared())\n",
+		"Comp.svelte": "<script>\n  export let title = '';\n  let count = 0;\n  count += 1;\n</script>\n\n<h1>{title}</h1>\n",
+	}
+	cfg, err := ParseConfig([]byte(`{"ignoreNames": ["ignored_*"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := New()
+	a.AnalyzeWorkspace(workspaceRequest(files, cfg))
+
+	tests := []struct {
+		file, symbol string
+		kind         string
+		used         bool
+		reported     bool
+		exemption    string
+		refs         []string // "rule file:line:column"
+	}{
+		{file: "lib.py", symbol: "local", kind: "function", used: true, refs: []string{"local lib.py:10:1"}},
+		{file: "lib.py", symbol: "os", kind: "import", used: true, refs: []string{"local lib.py:5:12"}},
+		{file: "lib.py", symbol: "sys", kind: "import", reported: true},
+		{file: "lib.py", symbol: "json", kind: "import", exemption: "a suppression comment covers it"},
+		{file: "lib.py", symbol: "ignored_helper", kind: "function", exemption: "the name is ignored by the configuration"},
+		{file: "lib.py", symbol: "_unused", kind: "parameter", exemption: "the name marks the parameter as intentionally unused"},
+		{file: "lib.py", symbol: "arg", kind: "parameter", reported: true},
+		{file: "lib.py", symbol: "shared", kind: "function", used: true, refs: []string{"cross-file app.py:4:17"}},
+		{file: "lib.py", symbol: "missing"},
+		{file: "lib/lib.go", symbol: "helper", kind: "function", used: true, refs: []string{"type-checked lib/lib.go:13:13"}},
+		{file: "lib/lib.go", symbol: "Count", kind: "variable", used: true, refs: []string{"type-checked main.go:7:10"}},
+		{file: "lib/lib.go", symbol: "impl.Run", kind: "method", used: true, refs: []string{"implicit lib/lib.go:0:0"}},
+		{file: "Comp.svelte", symbol: "count", kind: "variable", used: true, refs: []string{"local Comp.svelte:4:3"}},
+		{file: "Comp.svelte", symbol: "title", kind: "variable", used: true, refs: []string{"template Comp.svelte:7:6"}},
+	}
+	for _, tt := range tests {
+		ex := a.Explain(ExplainRequest{Filename: tt.file, Symbol: tt.symbol})
+		refs := []string{}
+		for _, ref := range ex.References {
+			refs = append(refs, fmt.Sprintf("%s %s:%d:%d", ref.Rule, ref.File, ref.Line, ref.Column))
+		}
+		if tt.refs == nil {
+			tt.refs = []string{}
+		}
+		if ex.Kind != tt.kind || ex.Used != tt.used || ex.Reported != tt.reported || ex.Exemption != tt.exemption {
+			t.Errorf("%s %s: kind %q, used %v, reported %v, exemption %q; want %q, %v, %v, %q",
+				tt.file, tt.symbol, ex.Kind, ex.Used, ex.Reported, ex.Exemption, tt.kind, tt.used, tt.reported, tt.exemption)
+		}
+		if !reflect.DeepEqual(refs, tt.refs) {
+			t.Errorf("%s %s: references %v, want %v", tt.file, tt.symbol, refs, tt.refs)
+		}
+	}
+
+	if ex := New().Explain(ExplainRequest{Filename: "lib.py", Symbol: "local"}); ex.Kind != "" || len(ex.References) != 0 {
+		t.Errorf("before any workspace: %+v, want nothing explained", ex)
+	}
+}
+
+func TestExplainDisabledRuleAndEntryPoint(t *testing.T) {
+	files := map[string]string{
+		"cli.py":  "import os\n\ndef run(argv):\n    pass\n",
+		"main.go": "package main\n\nfunc main() {}\n\nfunc Exported() {}\n",
+		"go.mod":  "module example.com/app\n",
+	}
+	cfg, err := ParseConfig([]byte(`{"exports": true, "entryPoints": ["main.go", "cli.py"], "languages": {"python": {"imports": false}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := New()
+	a.AnalyzeWorkspace(workspaceRequest(files, cfg))
+	tests := []struct {
+		file, symbol, exemption string
+	}{
+		{"cli.py", "os", "the rule is disabled by the configuration"},
+		{"cli.py", "argv", ""},
+		{"cli.py", "run", "the file is an entry point exporting it"},
+		// Nothing imports package main, so its names are not exported.
+		{"main.go", "Exported", ""},
+	}
+	for _, tt := range tests {
+		// An unused name is either reported or exempt.
+		ex := a.Explain(ExplainRequest{Filename: tt.file, Symbol: tt.symbol})
+		if ex.Used || ex.Reported != (tt.exemption == "") || ex.Exemption != tt.exemption {
+			t.Errorf("%s %s: used %v, reported %v, exemption %q, want unused and %q", tt.file, tt.symbol, ex.Used, ex.Reported, ex.Exemption, tt.exemption)
+		}
+	}
+}
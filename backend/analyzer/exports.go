@@ -0,0 +1,44 @@
+package analyzer
+
+import "strings"
+
+// unusedExports returns the findings for the exported definitions of
+// parsed whose "name@filename" key imported lacks and that have no finding
+// in variables. imported holds the keys an ExportResolver resolved, or else
+// those of the names other files use. Entry points report none, since what
+// they export is used from outside the workspace.
+func unusedExports(la LanguageAnalyzer, file AnalyzeFile, parsed ParsedFile, imported map[string]bool, cfg *Config, root string, variables []CodeIssue) []CodeIssue {
+	issues := []CodeIssue{}
+	rules := cfg.rulesFor(root, file.Filename, la.Language())
+	if !rules.exports || rules.ignored || rules.entryPoint {
+		return issues
+	}
+
+	reported := make(map[string]bool, len(variables))
+	for _, issue := range variables {
+		reported[issue.Symbol] = true
+	}
+	ids := newIssueBuilder(la, file, root)
+	sup := parseSuppressions(file.Content, la.Comments())
+	for _, def := range parsed.Definitions {
+		if !def.Exported || def.Name == "" || reported[def.Name] || rules.ignoresName(def.Name) {
+			continue
+		}
+		kind := definitionKind(def)
+		if imported[def.Name+"@"+file.Filename] || sup.suppressed(def.Line, "export", kind) {
+			continue
+		}
+		member := def.Name[strings.LastIndex(def.Name, ".")+1:]
+		at := CodeIssue{
+			Line:   def.Line,
+			Column: def.Column,
+			Text:   kind + " " + def.Name,
+			Kind:   kind,
+			Symbol: def.Name,
+		}
+		if issue, ok := ids.issue("export", ids.locate(at, member)); ok {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUnusedExports(t *testing.T) {
+	files := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.22\n",
+		"lib/lib.go":      "package lib\n\nfunc Used() int {\n\tKept()\n\treturn Local()\n}\n\nfunc Local() int { return 1 }\n\nfunc Unused() {}\n\n// unused-ignore-next-line export\nfunc Kept() {}\n",
+		"lib/lib_test.go": "package lib\n\nimport \"testing\"\n\nfunc TestUsed(t *testing.T) { Used() }\n\nfunc Helper() { Helper() }\n",
+		"main.go":         "package main\n\nimport \"example.com/app/lib\"\n\nfunc main() {\n\tprintln(lib.Used())\n\tRun()\n}\n\nfunc Run() {}\n",
+		"pkg.py":          "def shout():\n    pass\n\ndef imported():\n    pass\n\ndef _private():\n    pass\n\n_private()\nshout()\n",
+		"listed.py":       "__all__ = ['api']\n\ndef api():\n    pass\n\ndef other():\n    pass\n\napi()\nother()\n",
+		"cli.py":          "from pkg import imported\n\ndef start():\n    imported()\n\nstart()\n",
+		"Service.php":     "<?php\nnamespace App;\n\nclass Service {\n    public function run() {\n        return $this->check() + $this->hide();\n    }\n\n    public function check() {\n        return 0;\n    }\n\n    private function hide() {\n        return 1;\n    }\n}\n",
+		"index.php":       "<?php\nuse App\\Service;\n\n(new Service())->run();\n",
+		"models.rb":       "class Model\n  def save\n    validate\n    secret\n  end\n\n  def validate\n  end\n\n  private\n\n  def secret\n    true\n  end\nend\n",
+		"jobs.rb":         "require_relative 'models'\n\nModel.new.save\n",
+		"Comp.svelte":     "<script>\n  export let title = '';\n  export let size = 1;\n</script>\n\n<h1 class={size}>{title}</h1>\n",
+		"Page.svelte":     "<script>\n  import Comp from './Comp.svelte';\n</script>\n\n<Comp title=\"x\" />\n",
+	}
+	// Not exports: lib.Unused, which is reported as unused variable, Kept,
+	// which is suppressed, and the names of package main and of test files,
+	// which no other package can import.
+	tests := []struct {
+		config string
+		want   []string
+	}{
+		{`{}`, []string{}},
+		{`{"exports": true}`, []string{
+			"lib/lib.go:8:6 go/unused-export function Local",
+			"listed.py:3:5 python/unused-export function api",
+			"pkg.py:1:5 python/unused-export function shout",
+			"cli.py:3:5 python/unused-export function start",
+			"Service.php:9:21 php/unused-export method check",
+			"models.rb:7:7 ruby/unused-export method validate",
+			"Comp.svelte:3:14 svelte/unused-export variable size",
+		}},
+		{`{"exports": true, "entryPoints": ["cli.py", "*.rb"], "ignoreNames": ["api"]}`, []string{
+			"lib/lib.go:8:6 go/unused-export function Local",
+			"pkg.py:1:5 python/unused-export function shout",
+			"Service.php:9:21 php/unused-export method check",
+			"Comp.svelte:3:14 svelte/unused-export variable size",
+		}},
+		{`{"exports": true, "languages": {"go": {"exports": false}}}`, []string{
+			"listed.py:3:5 python/unused-export function api",
+			"pkg.py:1:5 python/unused-export function shout",
+			"cli.py:3:5 python/unused-export function start",
+			"Service.php:9:21 php/unused-export method check",
+			"models.rb:7:7 ruby/unused-export method validate",
+			"Comp.svelte:3:14 svelte/unused-export variable size",
+		}},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseConfig([]byte(tt.config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for filename, res := range New().AnalyzeWorkspace(workspaceRequest(files, cfg)).Results {
+			for _, issue := range res.Exports {
+				got = append(got, fmt.Sprintf("%s:%d:%d %s %s %s", filename, issue.Line, issue.Column, issue.RuleID, issue.Kind, issue.Symbol))
+			}
+		}
+		sort.Strings(got)
+		sort.Strings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("config %s: unused exports\n%q\nwant\n%q", tt.config, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// sourceText converts between positions and byte offsets of content.
+type sourceText struct {
+	content string
+	starts  []int
+}
+
+func newSourceText(content string) *sourceText {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &sourceText{content: content, starts: starts}
+}
+
+// lineEnd returns the offset of the newline ending the line containing
+// offset, or the end of content.
+func (s *sourceText) lineEnd(offset int) int {
+	if i := strings.IndexByte(s.content[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(s.content)
+}
+
+// lineStart returns the offset of the start of the line containing offset.
+func (s *sourceText) lineStart(offset int) int {
+	return strings.LastIndexByte(s.content[:offset], '\n') + 1
+}
+
+// offset converts a 1-based line and UTF-16 column to a byte offset. Out of
+// range positions are clamped to the content.
+func (s *sourceText) offset(line, column int) int {
+	if line < 1 {
+		return 0
+	}
+	if line > len(s.starts) {
+		return len(s.content)
+	}
+	start := s.starts[line-1]
+	end := s.lineEnd(start)
+	units := 0
+	for i, r := range s.content[start:end] {
+		if units >= column-1 {
+			return start + i
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return end
+}
+
+// position converts a byte offset to a 1-based line and UTF-16 column.
+func (s *sourceText) position(offset int) (line, column int) {
+	line = sort.Search(len(s.starts), func(i int) bool { return s.starts[i] > offset })
+	start := s.starts[line-1]
+	return line, utf16Column(s.content[start:s.lineEnd(start)], offset-start)
+}
+
+func (s *sourceText) span(start, end int) Span {
+	var sp Span
+	sp.Line, sp.Column = s.position(start)
+	sp.EndLine, sp.EndColumn = s.position(end)
+	return sp
+}
+
+func (s *sourceText) offsets(sp Span) (start, end int) {
+	return s.offset(sp.Line, sp.Column), s.offset(sp.EndLine, sp.EndColumn)
+}
+
+// deletion returns the edit deleting content[start:end].
+func (s *sourceText) deletion(file string, start, end int) TextEdit {
+	return TextEdit{File: file, Span: s.span(start, end)}
+}
+
+// ownLines reports whether content[start:end] is alone on its lines apart
+// from whitespace, a trailing separator and a trailing line comment, and
+// returns the range of those lines including the final newline.
+func (s *sourceText) ownLines(start, end int, syntax CommentSyntax) (int, int, bool) {
+	lineStart := s.lineStart(start)
+	if strings.TrimSpace(s.content[lineStart:start]) != "" {
+		return 0, 0, false
+	}
+	lineEnd := s.lineEnd(end)
+	rest := strings.TrimSpace(s.content[end:lineEnd])
+	rest = strings.TrimSpace(strings.TrimLeft(rest, ",;"))
+	if rest != "" && !hasLineCommentPrefix(rest, syntax) {
+		return 0, 0, false
+	}
+	if lineEnd < len(s.content) {
+		return lineStart, lineEnd + 1, true
+	}
+	// The last line has no newline to take along, so the one before goes.
+	if lineStart > 0 {
+		lineStart--
+	}
+	return lineStart, lineEnd, true
+}
+
+func hasLineCommentPrefix(s string, syntax CommentSyntax) bool {
+	for _, marker := range syntax.Line {
+		if strings.HasPrefix(s, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyInBlock reports whether the statement spanning start to end is the
+// only one of an indented block, such as an import in "try:", so that
+// deleting it would leave the block empty. Comments, blank lines and the
+// statements of removed do not count.
+func (s *sourceText) onlyInBlock(start, end int, syntax CommentSyntax, removed [][2]int) bool {
+	lineStart, lineEnd := s.lineStart(start), s.lineEnd(end)
+	if rest := strings.TrimSpace(s.content[end:lineEnd]); rest != "" && !hasLineCommentPrefix(rest, syntax) {
+		return false
+	}
+	if before := strings.TrimSpace(s.content[lineStart:start]); before != "" {
+		return strings.HasSuffix(before, ":")
+	}
+	indent := start - lineStart
+	prevIndent, prev, ok := s.codeLine(lineStart-1, -1, syntax, removed)
+	if !ok || prevIndent >= indent || !strings.HasSuffix(prev, ":") {
+		return false
+	}
+	nextIndent, _, ok := s.codeLine(lineEnd+1, 1, syntax, removed)
+	return !ok || nextIndent < indent
+}
+
+// codeLine finds the closest line holding code from the line containing
+// offset on, searching up when dir is negative and down otherwise, and
+// returns its indentation and its code without a trailing line comment.
+// Lines starting in one of the ranges of removed are skipped.
+func (s *sourceText) codeLine(offset, dir int, syntax CommentSyntax, removed [][2]int) (indent int, code string, ok bool) {
+	for offset >= 0 && offset <= len(s.content) {
+		lineStart, lineEnd := s.lineStart(offset), s.lineEnd(offset)
+		line := s.content[lineStart:lineEnd]
+		code = strings.TrimSpace(line)
+		indent = len(line) - len(strings.TrimLeft(line, " \t"))
+		if code != "" && !hasLineCommentPrefix(code, syntax) && !inRanges(lineStart+indent, removed) {
+			for _, marker := range syntax.Line {
+				if i := strings.Index(code, marker); i > 0 && !strings.HasSuffix(code, ":") {
+					code = strings.TrimSpace(code[:i])
+				}
+			}
+			return indent, code, true
+		}
+		if dir < 0 {
+			offset = lineStart - 1
+		} else {
+			offset = lineEnd + 1
+		}
+	}
+	return 0, "", false
+}
+
+// inRanges reports whether offset lies in one of ranges.
+func inRanges(offset int, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// statementDeletion deletes an import statement spanning start to end,
+// along with its line when nothing else is on it.
+func (s *sourceText) statementDeletion(file string, start, end int, syntax CommentSyntax) TextEdit {
+	if from, to, ok := s.ownLines(start, end, syntax); ok {
+		return s.deletion(file, from, to)
+	}
+	for end < len(s.content) && s.content[end] == ';' {
+		end++
+	}
+	for end < len(s.content) && (s.content[end] == ' ' || s.content[end] == '\t') {
+		end++
+	}
+	return s.deletion(file, start, end)
+}
+
+// itemDeletion deletes one item of a comma separated list, such as a name
+// of a statement that binds several or an argument, together with the comma
+// separating it from its neighbors. An item that is alone in braces, like
+// "b" in "a, { b }", takes the braces along.
+func (s *sourceText) itemDeletion(file string, start, end int, syntax CommentSyntax) TextEdit {
+	if from, to, ok := s.ownLines(start, end, syntax); ok {
+		return s.deletion(file, from, to)
+	}
+
+	before := skipSpaceBack(s.content, start)
+	after := skipSpaceForward(s.content, end)
+	if before > 0 && after < len(s.content) && s.content[before-1] == '{' && s.content[after] == '}' {
+		start, end = before-1, after+1
+		before = skipSpaceBack(s.content, start)
+		after = skipSpaceForward(s.content, end)
+	}
+
+	if after < len(s.content) && s.content[after] == ',' {
+		return s.deletion(file, start, skipSpaceForward(s.content, after+1))
+	}
+	if before > 0 && s.content[before-1] == ',' {
+		return s.deletion(file, skipSpaceBack(s.content, before-1), end)
+	}
+	return s.deletion(file, start, end)
+}
+
+// skipSpaceBack returns the offset before the spaces and tabs preceding i.
+func skipSpaceBack(content string, i int) int {
+	for i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+		i--
+	}
+	return i
+}
+
+// skipSpaceForward returns the offset after the spaces and tabs at i.
+func skipSpaceForward(content string, i int) int {
+	for i < len(content) && (content[i] == ' ' || content[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// importFixes returns the fix for every import of imports that unused
+// marks, keyed by index. A statement whose names are all unused is
+// deleted, otherwise only the name with its separating comma, and either
+// takes its line along when alone on it. Imports without a statement span
+// or of low confidence, which could still be needed, get no fix.
+func importFixes(la LanguageAnalyzer, file AnalyzeFile, imports []Import, unused map[int]bool) map[int]*Fix {
+	fixes := make(map[int]*Fix)
+	if len(unused) == 0 {
+		return fixes
+	}
+
+	statements := make(map[Span][]int)
+	for i, imp := range imports {
+		if imp.Statement.Line > 0 {
+			statements[imp.Statement] = append(statements[imp.Statement], i)
+		}
+	}
+
+	src := newSourceText(file.Content)
+	syntax := la.Comments()
+	// removed holds the statements deleted as a whole, which do not count
+	// as the statements of their block, see onlyInBlock.
+	var removed [][2]int
+	for stmt, members := range statements {
+		if allImportsUnused(members, unused) {
+			start, end := src.offsets(stmt)
+			removed = append(removed, [2]int{start, end})
+		}
+	}
+	blocks, indented := la.(IndentedBlocks)
+	for stmt, members := range statements {
+		allUnused := allImportsUnused(members, unused)
+		for _, i := range members {
+			if !unused[i] || imports[i].Confidence == ConfidenceLow {
+				continue
+			}
+			var edit TextEdit
+			if allUnused {
+				start, end := src.offsets(stmt)
+				if indented && src.onlyInBlock(start, end, syntax, removed) {
+					edit = TextEdit{File: file.Filename, Span: src.span(start, end), NewText: blocks.EmptyStatement()}
+				} else {
+					edit = src.statementDeletion(file.Filename, start, end, syntax)
+				}
+			} else {
+				start, end := src.offsets(Span{
+					Line:      imports[i].Line,
+					Column:    imports[i].Column,
+					EndLine:   imports[i].EndLine,
+					EndColumn: imports[i].EndColumn,
+				})
+				edit = src.itemDeletion(file.Filename, start, end, syntax)
+			}
+			fixes[i] = &Fix{Title: "Remove unused import " + imports[i].Name, Edits: []TextEdit{edit}}
+		}
+	}
+	return fixes
+}
+
+// allImportsUnused reports whether unused marks every import of members.
+func allImportsUnused(members []int, unused map[int]bool) bool {
+	for _, i := range members {
+		if !unused[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyTextEdits applies the edits for one file to content. Edits are
+// applied in order of position and repeated edits once; an edit overlapping
+// one already applied is skipped and returned, so the caller can apply it
+// after re-analyzing the result.
+func ApplyTextEdits(content string, edits []TextEdit) (string, []TextEdit) {
+	src := newSourceText(content)
+	type offsetEdit struct {
+		start, end int
+		edit       TextEdit
+	}
+	sorted := make([]offsetEdit, 0, len(edits))
+	for _, e := range edits {
+		start, end := src.offsets(e.Span)
+		sorted = append(sorted, offsetEdit{start: start, end: end, edit: e})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].start != sorted[j].start {
+			return sorted[i].start < sorted[j].start
+		}
+		return sorted[i].end < sorted[j].end
+	})
+
+	var b strings.Builder
+	var skipped []TextEdit
+	pos := 0
+	var last *offsetEdit
+	for i, e := range sorted {
+		if last != nil && last.start == e.start && last.end == e.end && last.edit.NewText == e.edit.NewText {
+			continue
+		}
+		if e.start < pos || (last != nil && last.start == e.start && last.end == e.end) {
+			skipped = append(skipped, e.edit)
+			continue
+		}
+		b.WriteString(content[pos:e.start])
+		b.WriteString(e.edit.NewText)
+		pos = e.end
+		last = &sorted[i]
+	}
+	b.WriteString(content[pos:])
+	return b.String(), skipped
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import "testing"
+
+// importFix returns the fix of the unused import symbol of content, or nil.
+func importFix(t *testing.T, filename, content, symbol string) *Fix {
+	t.Helper()
+	res := New().Analyze(AnalyzeRequest{Filename: filename, Content: content})
+	for _, issue := range res.Imports {
+		if issue.Symbol == symbol {
+			return issue.Fix
+		}
+	}
+	t.Fatalf("%s: no unused import %q in %+v", filename, symbol, res.Imports)
+	return nil
+}
+
+func TestImportFixes(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		symbol   string
+		want     string
+	}{
+		{
+			"a.py",
+			"import os\nimport sys\n\nprint(sys.argv)\n",
+			"os",
+			"import sys\n\nprint(sys.argv)\n",
+		},
+		{
+			"a.py",
+			"from x import a, b\n\nprint(a)\n",
+			"b",
+			"from x import a\n\nprint(a)\n",
+		},
+		{
+			"a.py",
+			"from x import a, b\n\nprint(b)\n",
+			"a",
+			"from x import b\n\nprint(b)\n",
+		},
+		{
+			"a.py",
+			"from x import (\n    a,\n    b,\n)\n\nprint(a)\n",
+			"b",
+			"from x import (\n    a,\n)\n\nprint(a)\n",
+		},
+		{
+			"a.py",
+			"try:\n    from utils import maybe_missing\nexcept ImportError:\n    pass\n",
+			"maybe_missing",
+			"try:\n    pass\nexcept ImportError:\n    pass\n",
+		},
+		{
+			"a.py",
+			"from typing import TYPE_CHECKING\n\nif TYPE_CHECKING:  # types only\n    import os\n\n    # comment\nprint(1)\n",
+			"os",
+			"from typing import TYPE_CHECKING\n\nif TYPE_CHECKING:  # types only\n    pass\n\n    # comment\nprint(1)\n",
+		},
+		{
+			"a.py",
+			"if DEBUG: import os\n",
+			"os",
+			"if DEBUG: pass\n",
+		},
+		{
+			"a.py",
+			"def f():\n    import os\n    return 1\n",
+			"os",
+			"def f():\n    return 1\n",
+		},
+		{
+			"main.go",
+			"package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			"os",
+			"package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println()\n}\n",
+		},
+		{
+			"main.go",
+			"package main\n\nimport \"os\"\n\nfunc main() {\n}\n",
+			"os",
+			"package main\n\n\nfunc main() {\n}\n",
+		},
+		{
+			"a.php",
+			"<?php\nuse App\\{Foo, Bar};\n\nnew Foo();\n",
+			"Bar",
+			"<?php\nuse App\\{Foo};\n\nnew Foo();\n",
+		},
+		{
+			"a.php",
+			"<?php\nuse App\\Foo;\nuse App\\Bar;\n\nnew Foo();\n",
+			"Bar",
+			"<?php\nuse App\\Foo;\n\nnew Foo();\n",
+		},
+		{
+			"App.vue",
+			"<script>\nimport { a, b } from './x';\nexport default { data() { return { v: a } } };\n</script>\n",
+			"b",
+			"<script>\nimport { a } from './x';\nexport default { data() { return { v: a } } };\n</script>\n",
+		},
+	}
+	for _, tt := range tests {
+		fix := importFix(t, tt.filename, tt.content, tt.symbol)
+		if fix == nil {
+			t.Errorf("%s: unused import %q has no fix", tt.filename, tt.symbol)
+			continue
+		}
+		got, skipped := ApplyTextEdits(tt.content, fix.Edits)
+		if len(skipped) > 0 {
+			t.Errorf("%s: %q skipped edits %+v", tt.filename, tt.symbol, skipped)
+		}
+		if got != tt.want {
+			t.Errorf("%s: removing %q gives\n%q\nwant\n%q", tt.filename, tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestImportFixesKeepBlocksNonEmpty(t *testing.T) {
+	content := "try:\n    import a\n    import b\nexcept ImportError:\n    pass\n"
+	var edits []TextEdit
+	for _, symbol := range []string{"a", "b"} {
+		edits = append(edits, importFix(t, "a.py", content, symbol).Edits...)
+	}
+	got, _ := ApplyTextEdits(content, edits)
+	if want := "try:\n    pass\n    pass\nexcept ImportError:\n    pass\n"; got != want {
+		t.Errorf("removing every import of a block gives\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestImportFixesSkipUnconfirmedImports(t *testing.T) {
+	content := "require 'json'\n\nputs 1\n"
+	if fix := importFix(t, "a.rb", content, "json"); fix != nil {
+		t.Errorf("low confidence Ruby require has fix %+v", fix)
+	}
+}
+
+func TestApplyTextEditsSkipsOverlaps(t *testing.T) {
+	content := "abcdef\nghi\n"
+	edit := func(startCol, endCol int, text string) TextEdit {
+		return TextEdit{Span: Span{Line: 1, Column: startCol, EndLine: 1, EndColumn: endCol}, NewText: text}
+	}
+	tests := []struct {
+		edits   []TextEdit
+		want    string
+		skipped int
+	}{
+		{[]TextEdit{edit(1, 3, "")}, "cdef\nghi\n", 0},
+		{[]TextEdit{edit(5, 7, "X"), edit(1, 2, "Y")}, "YbcdX\nghi\n", 0},
+		{[]TextEdit{edit(1, 4, ""), edit(2, 5, "")}, "def\nghi\n", 1},
+		{[]TextEdit{edit(3, 3, "+")}, "ab+cdef\nghi\n", 0},
+	}
+	for _, tt := range tests {
+		got, skipped := ApplyTextEdits(content, tt.edits)
+		if got != tt.want || len(skipped) != tt.skipped {
+			t.Errorf("ApplyTextEdits(%+v) = %q with %d skipped, want %q with %d", tt.edits, got, len(skipped), tt.want, tt.skipped)
+		}
+	}
+}
@@ -0,0 +1,572 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	gotoken "go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Workspace analysis for Go groups files into packages by directory and
+// package clause, derives import paths from the nearest go.mod and
+// type-checks the packages against each other. A definition is then used
+// exactly when go/types resolves a reference to it: unexported names can
+// only be reached from their own package, exported ones additionally from
+// importers that select them as pkg.Name. Imports are resolved against the
+// real package names of workspace packages, including dot imports. Build
+// constraints are not evaluated: files for different platforms are checked
+// together, and a name they each declare is used when one of them is.
+
+// goPackage is the set of files in one directory sharing a package clause.
+type goPackage struct {
+	dir       string
+	name      string
+	path      string
+	files     []*ast.File
+	filenames []string
+	// constrained tells which files have build constraints, see
+	// goConstrained.
+	constrained []bool
+	types       *types.Package
+	checking    bool
+}
+
+type goModule struct {
+	dir  string
+	path string
+}
+
+type goWorkspace struct {
+	fset     *gotoken.FileSet
+	info     *types.Info
+	packages []*goPackage
+	byPath   map[string]*goPackage
+	modules  []goModule
+}
+
+func (goAnalyzer) ResolveWorkspace(files []AnalyzeFile) map[string]bool {
+	ws := newGoWorkspace(files)
+	for _, pkg := range ws.packages {
+		ws.check(pkg)
+	}
+	return ws.usedKeys()
+}
+
+// usedKeys returns the "name@filename" keys of the imports and
+// declarations of the checked workspace that are used.
+func (ws *goWorkspace) usedKeys() map[string]bool {
+	used := goUsedObjects(ws.info)
+
+	out := make(map[string]bool)
+	for _, pkg := range ws.packages {
+		for i, file := range pkg.files {
+			g := &goFile{fset: ws.fset, file: file, info: ws.info, used: used}
+			for _, imp := range g.imports() {
+				if g.importUsed(imp) {
+					out[goImportKey(imp.item.alias, imp.item.path)+"@"+pkg.filenames[i]] = true
+				}
+			}
+			for _, d := range g.declarations() {
+				if !d.local && g.isUsed(d.obj) {
+					out[d.name+"@"+pkg.filenames[i]] = true
+				}
+			}
+		}
+	}
+	ws.shareVariants(out)
+	return out
+}
+
+// shareVariants adds to out, which holds "name@filename" keys, the
+// declarations of files with build constraints whose name another
+// constrained file of the package declares under a key in out. Such files
+// are built for different platforms or tags, so each variant is used, but
+// go/types resolves every reference to the first one it sees.
+func (ws *goWorkspace) shareVariants(out map[string]bool) {
+	for _, pkg := range ws.packages {
+		declared := make(map[string][]string)
+		for i, file := range pkg.files {
+			if !pkg.constrained[i] {
+				continue
+			}
+			for _, name := range goSyntaxNames(file) {
+				declared[name] = append(declared[name], pkg.filenames[i])
+			}
+		}
+		for name, filenames := range declared {
+			if len(filenames) < 2 {
+				continue
+			}
+			for _, filename := range filenames {
+				if out[name+"@"+filename] {
+					for _, other := range filenames {
+						out[name+"@"+other] = true
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// goSyntaxNames returns the names goFile.declarations gives the
+// package-level declarations of file, read from the syntax alone: go/types
+// records no object for a name declared again in another file.
+func goSyntaxNames(file *ast.File) []string {
+	var names []string
+	members := func(owner string, spec *ast.TypeSpec) {
+		var list *ast.FieldList
+		switch t := spec.Type.(type) {
+		case *ast.StructType:
+			list = t.Fields
+		case *ast.InterfaceType:
+			list = t.Methods
+		default:
+			return
+		}
+		for _, field := range list.List {
+			for _, ident := range field.Names {
+				names = append(names, owner+"."+ident.Name)
+			}
+		}
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			} else if len(d.Recv.List) == 1 {
+				if owner := goReceiverType(d.Recv.List[0].Type); owner != "" {
+					names = append(names, owner+"."+d.Name.Name)
+				}
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+					members(s.Name.Name, s)
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						names = append(names, ident.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// goReceiverType returns the name of the type of a receiver such as "T",
+// "*T" or "*T[K]".
+func goReceiverType(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// goConstrained reports whether a file is only built under some
+// configurations: content has a //go:build or // +build line before the
+// package clause, or the filename ends in a GOOS or GOARCH suffix such as
+// _linux or _windows_amd64.
+func goConstrained(filename, content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if constraint.IsGoBuild(line) || constraint.IsPlusBuild(line) {
+			return true
+		}
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(path.Base(slashPath(filename)), ".go"), "_test")
+	parts := strings.Split(name, "_")
+	if len(parts) < 2 {
+		return false
+	}
+	last := parts[len(parts)-1]
+	return goKnownOS[last] || goKnownArch[last]
+}
+
+// goKnownOS and goKnownArch are the GOOS and GOARCH values go/build
+// recognizes in filename suffixes.
+var (
+	goKnownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true,
+		"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+		"js": true, "linux": true, "nacl": true, "netbsd": true,
+		"openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	goKnownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true,
+		"armbe": true, "arm64": true, "arm64be": true, "loong64": true,
+		"mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+		"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true,
+		"s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// ResolveExports type-checks the workspace as ResolveWorkspace does and
+// returns the keys of the exported declarations referenced from another
+// package. Members that satisfy an interface or are set by an unkeyed
+// composite literal count as referenced, since go/types records no
+// reference for them.
+func (goAnalyzer) ResolveExports(files []AnalyzeFile) map[string]bool {
+	ws := newGoWorkspace(files)
+	byFile := make(map[string]*goPackage)
+	for _, pkg := range ws.packages {
+		ws.check(pkg)
+		for _, filename := range pkg.filenames {
+			byFile[filename] = pkg
+		}
+	}
+
+	imported := make(map[types.Object]bool)
+	for ident, obj := range ws.info.Uses {
+		if pkg := byFile[ws.fset.Position(ident.Pos()).Filename]; pkg != nil && pkg.types != obj.Pkg() {
+			imported[obj] = true
+		}
+	}
+	implicit := goUsedObjects(&types.Info{Types: ws.info.Types, Defs: ws.info.Defs})
+
+	out := make(map[string]bool)
+	for _, pkg := range ws.packages {
+		for i, file := range pkg.files {
+			g := &goFile{fset: ws.fset, file: file, info: ws.info}
+			for _, d := range g.declarations() {
+				if !d.local && (imported[d.obj] || implicit[d.obj]) {
+					out[d.name+"@"+pkg.filenames[i]] = true
+				}
+			}
+		}
+	}
+	ws.shareVariants(out)
+	return out
+}
+
+// IsTestFile reports whether filename is a _test.go file.
+func (goAnalyzer) IsTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
+}
+
+// ResolveImports resolves import paths with the go.mod files of the
+// workspace or, when it has none, by the end of the directory path, as
+// ResolveWorkspace does. A file depends on every file of the packages it
+// imports and of its own package, but not on test files.
+func (goAnalyzer) ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string {
+	var modules []goModule
+	for _, filename := range ix.Find("go.mod") {
+		if modPath := goModulePath(ix.Content(indexPath(filename))); modPath != "" {
+			modules = append(modules, goModule{dir: path.Dir(indexPath(filename)), path: modPath})
+		}
+	}
+
+	deps := goPackageFiles(ix, path.Dir(indexPath(file.Filename)))
+	for _, imp := range parsed.Imports {
+		var dirs []string
+		for _, mod := range modules {
+			if imp.Source == mod.path {
+				dirs = append(dirs, mod.dir)
+			} else if rest, ok := strings.CutPrefix(imp.Source, mod.path+"/"); ok {
+				dirs = append(dirs, path.Join(mod.dir, rest))
+			}
+		}
+		if len(modules) == 0 {
+			dirs = ix.FindDirs(imp.Source)
+		}
+		for _, dir := range dirs {
+			deps = append(deps, goPackageFiles(ix, dir)...)
+		}
+	}
+	return deps
+}
+
+// goPackageFiles returns the Go files of dir besides tests.
+func goPackageFiles(ix *FileIndex, dir string) []string {
+	var files []string
+	for _, filename := range ix.Dir(dir) {
+		if strings.HasSuffix(filename, ".go") && !strings.HasSuffix(filename, "_test.go") {
+			files = append(files, filename)
+		}
+	}
+	return files
+}
+
+// FindReferences type-checks the workspace as ResolveWorkspace does and
+// returns the identifiers go/types resolves to the declaration of name in
+// file. Uses without such an identifier, such as methods satisfying an
+// interface, are returned as one implicit reference.
+func (goAnalyzer) FindReferences(files []AnalyzeFile, file AnalyzeFile, _ ParsedFile, name string) []Reference {
+	ws := newGoWorkspace(files)
+	for _, pkg := range ws.packages {
+		ws.check(pkg)
+	}
+
+	var g *goFile
+	for _, pkg := range ws.packages {
+		for i, f := range pkg.files {
+			if pkg.filenames[i] == file.Filename {
+				g = &goFile{fset: ws.fset, file: f, info: ws.info, used: goUsedObjects(ws.info)}
+			}
+		}
+	}
+	if g == nil {
+		return nil
+	}
+
+	lines := make(map[string][]string)
+	var refs []Reference
+	uses := func(match func(ident *ast.Ident, obj types.Object) bool) {
+		for ident, obj := range ws.info.Uses {
+			if !match(ident, obj) {
+				continue
+			}
+			p := ws.fset.Position(ident.Pos())
+			if lines[p.Filename] == nil {
+				for _, f := range files {
+					if f.Filename == p.Filename {
+						lines[p.Filename] = strings.Split(f.Content, "\n")
+					}
+				}
+			}
+			ref := Reference{File: p.Filename, Span: Span{Line: p.Line, Column: p.Column}, Rule: RuleTypeChecked}
+			if src := lines[p.Filename]; p.Line >= 1 && p.Line <= len(src) {
+				ref.Column = utf16Column(src[p.Line-1], p.Column-1)
+				ref.Text = strings.TrimSpace(src[p.Line-1])
+			}
+			ref.EndLine, ref.EndColumn = ref.Line, ref.Column+utf16Len(ident.Name)
+			refs = append(refs, ref)
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].File != refs[j].File {
+				return refs[i].File < refs[j].File
+			}
+			if refs[i].Line != refs[j].Line {
+				return refs[i].Line < refs[j].Line
+			}
+			return refs[i].Column < refs[j].Column
+		})
+	}
+	implicit := func(text string) []Reference {
+		return []Reference{{File: file.Filename, Rule: RuleImplicit, Text: text}}
+	}
+
+	for _, imp := range g.imports() {
+		if goImportKey(imp.item.alias, imp.item.path) != name {
+			continue
+		}
+		switch {
+		case imp.item.alias == "_":
+			return implicit("blank import, kept for its side effects")
+		case imp.obj == nil:
+		case imp.item.alias == ".":
+			imported := imp.obj.Imported()
+			uses(func(ident *ast.Ident, obj types.Object) bool {
+				return obj.Pkg() == imported && g.contains(ident.Pos())
+			})
+		default:
+			uses(func(_ *ast.Ident, obj types.Object) bool { return obj == imp.obj })
+		}
+		if len(refs) == 0 && g.importUsed(imp) {
+			return implicit("package outside the workspace, matched by the name of an unresolved identifier")
+		}
+		return refs
+	}
+
+	for _, d := range g.declarations() {
+		if d.name != name {
+			continue
+		}
+		uses(func(_ *ast.Ident, obj types.Object) bool { return obj == d.obj })
+		if len(refs) == 0 && g.isUsed(d.obj) {
+			return implicit(goImplicitUse(d.obj))
+		}
+		return refs
+	}
+	// go/types declares no object for a name declared again in a file for
+	// another build configuration, see shareVariants.
+	if ws.usedKeys()[name+"@"+file.Filename] {
+		return implicit("declared for another build configuration as well, where it is used")
+	}
+	return nil
+}
+
+// goImplicitUse describes why goFile.isUsed counts obj as used although
+// nothing refers to it.
+func goImplicitUse(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() == nil {
+			return "entry point of the program or package"
+		}
+		if goWellKnownMethods[obj.Name()] {
+			return "method name of a well-known interface, such as fmt.Stringer"
+		}
+		return "method satisfying an interface of the workspace"
+	case *types.Var:
+		if obj.IsField() {
+			return "field set by an unkeyed composite literal"
+		}
+	}
+	return "used implicitly"
+}
+
+func newGoWorkspace(files []AnalyzeFile) *goWorkspace {
+	ws := &goWorkspace{
+		fset:   gotoken.NewFileSet(),
+		info:   newGoInfo(),
+		byPath: make(map[string]*goPackage),
+	}
+
+	for _, f := range files {
+		if path.Base(slashPath(f.Filename)) == "go.mod" {
+			if modPath := goModulePath(f.Content); modPath != "" {
+				ws.modules = append(ws.modules, goModule{dir: path.Dir(slashPath(f.Filename)), path: modPath})
+			}
+		}
+	}
+	// Longest directory first, so nested modules win over their parents.
+	sort.Slice(ws.modules, func(i, j int) bool { return len(ws.modules[i].dir) > len(ws.modules[j].dir) })
+
+	byKey := make(map[string]*goPackage)
+	for _, f := range files {
+		if DetectLanguage(f.Filename) != LangGo {
+			continue
+		}
+		file, _ := parser.ParseFile(ws.fset, f.Filename, f.Content, parser.SkipObjectResolution)
+		if file == nil || file.Name == nil {
+			continue
+		}
+
+		dir := path.Dir(slashPath(f.Filename))
+		key := dir + "|" + file.Name.Name
+		pkg, ok := byKey[key]
+		if !ok {
+			pkg = &goPackage{dir: dir, name: file.Name.Name, path: ws.importPath(dir)}
+			if strings.HasSuffix(pkg.name, "_test") {
+				pkg.path += "_test"
+			}
+			byKey[key] = pkg
+			ws.packages = append(ws.packages, pkg)
+		}
+		pkg.files = append(pkg.files, file)
+		pkg.filenames = append(pkg.filenames, f.Filename)
+		pkg.constrained = append(pkg.constrained, goConstrained(f.Filename, f.Content))
+	}
+
+	for _, pkg := range ws.packages {
+		if strings.HasSuffix(pkg.name, "_test") {
+			continue
+		}
+		if _, ok := ws.byPath[pkg.path]; !ok {
+			ws.byPath[pkg.path] = pkg
+		}
+	}
+	return ws
+}
+
+// importPath returns the import path of the package in dir. Without an
+// enclosing go.mod the directory itself is used and imports are matched by
+// suffix in lookup.
+func (ws *goWorkspace) importPath(dir string) string {
+	for _, mod := range ws.modules {
+		if dir == mod.dir {
+			return mod.path
+		}
+		if mod.dir == "." || strings.HasPrefix(dir, mod.dir+"/") {
+			rel := strings.TrimPrefix(dir, mod.dir+"/")
+			return mod.path + "/" + rel
+		}
+	}
+	return dir
+}
+
+func (ws *goWorkspace) lookup(importPath string) *goPackage {
+	if pkg, ok := ws.byPath[importPath]; ok {
+		return pkg
+	}
+	if len(ws.modules) > 0 {
+		return nil
+	}
+	for p, pkg := range ws.byPath {
+		if strings.HasSuffix(p, "/"+importPath) {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// check type-checks pkg once, checking workspace dependencies first so
+// their objects are shared with the importer. Import cycles fall back to
+// placeholder packages.
+func (ws *goWorkspace) check(pkg *goPackage) *types.Package {
+	if pkg.types != nil || pkg.checking {
+		return pkg.types
+	}
+	pkg.checking = true
+	defer func() { pkg.checking = false }()
+
+	conf := types.Config{
+		Importer:    goWorkspaceImporter{ws: ws, from: pkg},
+		FakeImportC: true,
+		Error:       func(error) {},
+	}
+	pkg.types, _ = conf.Check(pkg.path, ws.fset, pkg.files, ws.info)
+	return pkg.types
+}
+
+type goWorkspaceImporter struct {
+	ws   *goWorkspace
+	from *goPackage
+}
+
+func (imp goWorkspaceImporter) Import(importPath string) (*types.Package, error) {
+	if pkg := imp.ws.lookup(importPath); pkg != nil && pkg != imp.from {
+		if checked := imp.ws.check(pkg); checked != nil {
+			return checked, nil
+		}
+	}
+	return goImporter{}.Import(importPath)
+}
+
+func slashPath(filename string) string {
+	return strings.ReplaceAll(filename, "\\", "/")
+}
+
+// goModulePath extracts the module path from the contents of a go.mod file.
+func goModulePath(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+				return unquoted
+			}
+			return fields[1]
+		}
+	}
+	return ""
+}
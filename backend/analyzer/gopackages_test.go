@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGoBuildVariantsShareUsage(t *testing.T) {
+	files := []AnalyzeFile{
+		{Filename: "go.mod", Content: "module example.com/m\n\ngo 1.22\n"},
+		{Filename: "p/p.go", Content: "package p\n\nfunc Use() int { return plat() }\n"},
+		{Filename: "p/p_linux.go", Content: "package p\n\nfunc plat() int { return 1 }\n\nfunc linuxOnly() {}\n"},
+		{Filename: "p/p_windows_amd64.go", Content: "package p\n\nfunc plat() int { return 2 }\n"},
+		{Filename: "p/p_other.go", Content: "//go:build !linux && !windows\n\npackage p\n\nfunc plat() int { return 3 }\n"},
+		{Filename: "q/q.go", Content: "package q\n\nfunc plat() int { return 4 }\n"},
+		{Filename: "q/q_darwin.go", Content: "package q\n\nfunc other() int { return plat() }\n"},
+	}
+	res := New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: files}).Results
+	var got []string
+	for filename, r := range res {
+		for _, issue := range r.Variables {
+			got = append(got, issue.Symbol+"@"+filename)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"Use@p/p.go", "linuxOnly@p/p_linux.go", "other@q/q_darwin.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unused %v, want %v", got, want)
+	}
+}
+
+func TestGoConstrained(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		want     bool
+	}{
+		{"p.go", "package p\n", false},
+		{"p_linux.go", "package p\n", true},
+		{"p_windows_amd64.go", "package p\n", true},
+		{"p_arm64_test.go", "package p\n", true},
+		{"linux.go", "package p\n", false},
+		{"p_helper.go", "package p\n", false},
+		{"p.go", "// Copyright\n\n//go:build integration\n\npackage p\n", true},
+		{"p.go", "// +build linux\n\npackage p\n", true},
+		{"p.go", "package p\n\n//go:build ignored after the package clause\n", false},
+	}
+	for _, tt := range tests {
+		if got := goConstrained(tt.filename, tt.content); got != tt.want {
+			t.Errorf("goConstrained(%q, %q) = %v, want %v", tt.filename, tt.content, got, tt.want)
+		}
+	}
+}
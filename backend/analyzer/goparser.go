@@ -0,0 +1,834 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	gotoken "go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Go sources are parsed with go/parser and type-checked with go/types, so
+// identifiers are resolved through real scopes instead of being counted.
+// Imported packages are not loaded: goImporter hands out empty placeholder
+// packages, which is enough for go/types to attribute every "pkg.Name"
+// selector to the import it refers to.
+
+type GoImportItem struct {
+	name  string
+	alias string
+	path  string
+	line  int
+	text  string
+}
+
+type GoVariable struct {
+	Name string
+	Line int
+	Type string
+}
+
+type GoParameter struct {
+	Name string
+	Line int
+}
+
+// goFile is a parsed and type-checked Go source file. info and used may be
+// shared with the other files of a workspace.
+type goFile struct {
+	fset *gotoken.FileSet
+	file *ast.File
+	info *types.Info
+	used map[types.Object]bool
+	// lines holds the source for column conversion; it is only set by
+	// checkGoFile.
+	lines []string
+
+	unresolved *goUnresolved
+}
+
+// goUnresolved collects identifiers go/types could not resolve, which refer
+// into placeholder packages, sibling files that were not checked together,
+// or code that does not compile.
+type goUnresolved struct {
+	qualifiers map[string]bool
+	names      map[string]bool
+}
+
+// goDecl is a declared object together with how it is reported. Methods,
+// struct fields and interface methods are named after their type, e.g.
+// "T.Close", so that equally named members of different types stay apart.
+type goDecl struct {
+	obj    types.Object
+	name   string
+	kind   string
+	line   int
+	local  bool
+	member bool
+}
+
+type goImport struct {
+	item GoImportItem
+	obj  *types.PkgName
+	spec *ast.ImportSpec
+	decl *ast.GenDecl
+}
+
+type goImporter struct{}
+
+func (goImporter) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	pkg := types.NewPackage(path, goPackageName(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// goPackageName guesses the name a package is referred to by from its
+// import path, following common conventions: major version suffixes are
+// skipped ("example.com/mod/v2", "gopkg.in/yaml.v3"), "go-" prefixes and
+// "-go"/".go" suffixes are dropped and of the remaining hyphenated words the
+// last one is used ("github.com/hashicorp/golang-lru" -> "lru").
+func goPackageName(path string) string {
+	name := goPathElement(path)
+	if i := strings.LastIndex(name, ".v"); i > 0 && isGoMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	name = strings.TrimSuffix(name, ".go")
+	if i := strings.LastIndexAny(name, "-."); i >= 0 && i < len(name)-1 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func isGoMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// goImportKey is the name an import is reported and looked up under: its
+// alias, the guessed package name, or "." followed by the path for dot
+// imports so that several of them in one file stay distinct. The key does
+// not depend on whether the imported package could be loaded, so
+// single-file and workspace analysis agree on it.
+func goImportKey(alias, path string) string {
+	switch alias {
+	case "":
+		return goPackageName(path)
+	case ".":
+		return "." + path
+	}
+	return alias
+}
+
+// isPlaceholderPackage reports whether pkg was produced by goImporter
+// instead of being type-checked from source.
+func isPlaceholderPackage(pkg *types.Package) bool {
+	return pkg != nil && pkg != types.Unsafe && pkg.Scope().Len() == 0
+}
+
+// checkGoFile parses and type-checks a single file. Type errors, such as
+// references into placeholder packages, are ignored. It returns nil when
+// the file cannot be parsed at all.
+func checkGoFile(content, filename string) *goFile {
+	fset := gotoken.NewFileSet()
+	file, _ := parser.ParseFile(fset, filename, content, parser.SkipObjectResolution)
+	if file == nil || file.Name == nil {
+		return nil
+	}
+
+	info := newGoInfo()
+	conf := types.Config{
+		Importer:    goImporter{},
+		FakeImportC: true,
+		Error:       func(error) {},
+	}
+	conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+
+	return &goFile{fset: fset, file: file, info: info, used: goUsedObjects(info), lines: strings.Split(content, "\n")}
+}
+
+func newGoInfo() *types.Info {
+	return &types.Info{
+		Types:     make(map[ast.Expr]types.TypeAndValue),
+		Defs:      make(map[*ast.Ident]types.Object),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Implicits: make(map[ast.Node]types.Object),
+	}
+}
+
+// goWellKnownMethods are methods that satisfy interfaces of the standard
+// library and common packages. Those packages are not loaded, so
+// types.Implements cannot see them and the method name has to do.
+var goWellKnownMethods = map[string]bool{
+	"String": true, "GoString": true, "Format": true, "Error": true,
+	"Unwrap": true, "Is": true, "As": true,
+	"Read": true, "Write": true, "Close": true, "Seek": true,
+	"ReadFrom": true, "WriteTo": true, "ReadAt": true, "WriteAt": true,
+	"ReadByte": true, "WriteByte": true, "ReadRune": true, "WriteString": true,
+	"MarshalJSON": true, "UnmarshalJSON": true,
+	"MarshalText": true, "UnmarshalText": true,
+	"MarshalBinary": true, "UnmarshalBinary": true,
+	"MarshalYAML": true, "UnmarshalYAML": true,
+	"MarshalXML": true, "UnmarshalXML": true,
+	"GobEncode": true, "GobDecode": true,
+	"Len": true, "Less": true, "Swap": true, "Push": true, "Pop": true,
+	"Lock": true, "Unlock": true, "ServeHTTP": true, "RoundTrip": true,
+	"Scan": true, "Value": true,
+	"Deadline": true, "Done": true, "Err": true,
+	"Import": true, "Visit": true, "Sum": true, "Reset": true, "Size": true,
+	"BlockSize": true,
+}
+
+// goUsedObjects returns the objects referenced in info. Besides explicit
+// references it counts struct fields set by unkeyed composite literals and
+// methods that satisfy an interface: those of named interfaces in info that
+// the type implements, and the well-known methods of unloaded packages.
+func goUsedObjects(info *types.Info) map[types.Object]bool {
+	used := make(map[types.Object]bool)
+	for _, obj := range info.Uses {
+		used[obj] = true
+	}
+
+	for expr, tv := range info.Types {
+		lit, ok := expr.(*ast.CompositeLit)
+		if !ok || len(lit.Elts) == 0 || tv.Type == nil {
+			continue
+		}
+		if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+			continue
+		}
+		if st, ok := tv.Type.Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				used[st.Field(i)] = true
+			}
+		}
+	}
+
+	var named []*types.Named
+	var ifaces []*types.Interface
+	for _, obj := range info.Defs {
+		tn, ok := obj.(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		n, ok := tn.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		if iface, ok := n.Underlying().(*types.Interface); ok {
+			if iface.NumMethods() > 0 {
+				ifaces = append(ifaces, iface)
+			}
+			continue
+		}
+		named = append(named, n)
+	}
+
+	for _, n := range named {
+		for i := 0; i < n.NumMethods(); i++ {
+			if m := n.Method(i); goWellKnownMethods[m.Name()] {
+				used[m] = true
+			}
+		}
+		ptr := types.NewPointer(n)
+		for _, iface := range ifaces {
+			if !types.Implements(ptr, iface) {
+				continue
+			}
+			for i := 0; i < iface.NumMethods(); i++ {
+				m := iface.Method(i)
+				if obj, _, _ := types.LookupFieldOrMethod(ptr, false, m.Pkg(), m.Name()); obj != nil {
+					used[obj] = true
+				}
+			}
+		}
+	}
+	return used
+}
+
+func (g *goFile) line(pos gotoken.Pos) int {
+	return g.fset.Position(pos).Line
+}
+
+// position returns the line and UTF-16 column of pos.
+func (g *goFile) position(pos gotoken.Pos) (line, column int) {
+	p := g.fset.Position(pos)
+	if p.Line < 1 || p.Line > len(g.lines) {
+		return p.Line, p.Column
+	}
+	return p.Line, utf16Column(g.lines[p.Line-1], p.Column-1)
+}
+
+// isUsed reports whether obj is referenced anywhere besides its declaration.
+// The entry points main and init count as used, and so do the functions go
+// test runs.
+func (g *goFile) isUsed(obj types.Object) bool {
+	if g.used[obj] {
+		return true
+	}
+	if fn, ok := obj.(*types.Func); ok && fn.Parent() == fn.Pkg().Scope() {
+		return fn.Name() == "init" || (fn.Name() == "main" && fn.Pkg().Name() == "main") || g.isTestFunction(fn)
+	}
+	return false
+}
+
+// goTestPrefixes maps the prefixes of the functions go test runs to the
+// type of package testing their single parameter points to, or to "" for
+// examples, which take none.
+var goTestPrefixes = map[string]string{"Test": "T", "Benchmark": "B", "Fuzz": "F", "Example": ""}
+
+// isTestFunction reports whether fn is declared in a _test.go file with a
+// name and signature go test runs, such as "func TestX(t *testing.T)", or
+// is TestMain.
+func (g *goFile) isTestFunction(fn *types.Func) bool {
+	if !strings.HasSuffix(g.fset.Position(g.file.Package).Filename, "_test.go") {
+		return false
+	}
+	var decl *ast.FuncDecl
+	for _, d := range g.file.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && g.info.Defs[fd.Name] == fn {
+			decl = fd
+		}
+	}
+	if decl == nil || decl.Type.TypeParams != nil || decl.Type.Results.NumFields() > 0 {
+		return false
+	}
+
+	name := fn.Name()
+	for prefix, param := range goTestPrefixes {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(rest); unicode.IsLower(r) {
+			return false
+		}
+		if name == "TestMain" {
+			param = "M"
+		}
+		if param == "" {
+			return decl.Type.Params.NumFields() == 0
+		}
+		return decl.Type.Params.NumFields() == 1 && g.isTestingType(decl.Type.Params.List[0].Type, param)
+	}
+	return false
+}
+
+// isTestingType reports whether expr is *testing.<name>.
+func (g *goFile) isTestingType(expr ast.Expr, name string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkg, ok := g.info.Uses[ident].(*types.PkgName)
+	return ok && pkg.Imported().Path() == "testing"
+}
+
+func (g *goFile) unresolvedIdents() *goUnresolved {
+	if g.unresolved != nil {
+		return g.unresolved
+	}
+	u := &goUnresolved{qualifiers: make(map[string]bool), names: make(map[string]bool)}
+	skip := map[*ast.Ident]bool{g.file.Name: true}
+	ast.Inspect(g.file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+			if x, ok := n.X.(*ast.Ident); ok && g.isUnresolved(x) {
+				u.qualifiers[x.Name] = true
+				skip[x] = true
+			}
+		case *ast.KeyValueExpr:
+			if key, ok := n.Key.(*ast.Ident); ok {
+				skip[key] = true
+			}
+		case *ast.Ident:
+			if !skip[n] && g.isUnresolved(n) {
+				u.names[n.Name] = true
+			}
+		}
+		return true
+	})
+	g.unresolved = u
+	return u
+}
+
+func (g *goFile) isUnresolved(ident *ast.Ident) bool {
+	return ident.Name != "_" && g.info.Uses[ident] == nil && g.info.Defs[ident] == nil
+}
+
+// importUsed reports whether the file refers to imp. References into
+// packages that were not loaded are only visible through the import name.
+// Because that name is a guess, an unresolved qualifier that occurs in the
+// import path also counts, e.g. sqlite3.X for "github.com/mattn/go-sqlite3"
+// if the guess were wrong. Dot imports of unloaded packages count as used
+// as long as the file has any unresolved identifier.
+func (g *goFile) importUsed(imp goImport) bool {
+	if imp.item.alias == "_" {
+		return true
+	}
+	if imp.obj == nil {
+		return false
+	}
+
+	imported := imp.obj.Imported()
+	if imp.item.alias == "." {
+		if isPlaceholderPackage(imported) {
+			return len(g.unresolvedIdents().names) > 0
+		}
+		for ident, obj := range g.info.Uses {
+			if obj.Pkg() == imported && g.contains(ident.Pos()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if g.isUsed(imp.obj) {
+		return true
+	}
+	if imp.item.alias != "" || !isPlaceholderPackage(imported) {
+		return false
+	}
+	element := strings.ToLower(goPathElement(imp.item.path))
+	for q := range g.unresolvedIdents().qualifiers {
+		if strings.Contains(element, strings.ToLower(q)) {
+			return true
+		}
+	}
+	return false
+}
+
+// goPathElement returns the last element of an import path that is not a
+// major version suffix.
+func goPathElement(path string) string {
+	segments := strings.Split(path, "/")
+	last := segments[len(segments)-1]
+	if len(segments) > 1 && isGoMajorVersion(last) {
+		return segments[len(segments)-2]
+	}
+	return last
+}
+
+func (g *goFile) contains(pos gotoken.Pos) bool {
+	return g.file.Pos() <= pos && pos <= g.file.End()
+}
+
+func (g *goFile) imports() []goImport {
+	var out []goImport
+	for _, decl := range g.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != gotoken.IMPORT {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.ImportSpec)
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || path == "C" {
+				continue
+			}
+
+			item := GoImportItem{
+				path: path,
+				line: g.line(spec.Pos()),
+				text: "import " + spec.Path.Value,
+			}
+			var obj types.Object
+			if spec.Name != nil {
+				item.alias = spec.Name.Name
+				item.text = "import " + spec.Name.Name + " " + spec.Path.Value
+				obj = g.info.Defs[spec.Name]
+			} else {
+				obj = g.info.Implicits[spec]
+			}
+
+			pkgName, _ := obj.(*types.PkgName)
+			if pkgName != nil {
+				item.name = pkgName.Name()
+			} else if item.alias == "" {
+				item.name = goPackageName(path)
+			}
+			out = append(out, goImport{item: item, obj: pkgName, spec: spec, decl: gen})
+		}
+	}
+	return out
+}
+
+// declarations returns package-level functions, methods, types with their
+// struct fields and interface methods, variables and constants, followed
+// by local variables.
+func (g *goFile) declarations() []goDecl {
+	var out []goDecl
+	add := func(ident *ast.Ident, owner, kind string, local bool) {
+		if ident == nil || ident.Name == "_" {
+			return
+		}
+		obj := g.info.Defs[ident]
+		if obj == nil {
+			return
+		}
+		name := obj.Name()
+		if owner != "" {
+			name = owner + "." + name
+		}
+		out = append(out, goDecl{obj: obj, name: name, kind: kind, line: g.line(ident.Pos()), local: local, member: owner != ""})
+	}
+
+	for _, decl := range g.file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				add(d.Name, "", "function", false)
+			} else if owner := g.receiverName(d); owner != "" {
+				add(d.Name, owner, "method", false)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					add(s.Name, "", "type", false)
+					g.members(s, add)
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == gotoken.CONST {
+						kind = "constant"
+					}
+					for _, name := range s.Names {
+						add(name, "", kind, false)
+					}
+				}
+			}
+		}
+	}
+
+	ast.Inspect(g.file, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != gotoken.DEFINE {
+				return true
+			}
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					add(ident, "", "variable", true)
+				}
+			}
+		case *ast.DeclStmt:
+			gen, ok := s.Decl.(*ast.GenDecl)
+			if !ok || gen.Tok != gotoken.VAR {
+				return true
+			}
+			for _, spec := range gen.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					add(name, "", "variable", true)
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// receiverName returns the name of the type a method is declared on.
+func (g *goFile) receiverName(fn *ast.FuncDecl) string {
+	obj, ok := g.info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return ""
+	}
+	recv := obj.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if n, ok := t.(*types.Named); ok {
+		return n.Obj().Name()
+	}
+	return ""
+}
+
+// members adds the named struct fields and interface methods of a type
+// declaration. Embedded fields are left out as they mostly serve to promote
+// methods, and so are fields with a tag, which are typically read through
+// reflection by encoding packages.
+func (g *goFile) members(spec *ast.TypeSpec, add func(ident *ast.Ident, owner, kind string, local bool)) {
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if field.Tag != nil {
+				continue
+			}
+			for _, name := range field.Names {
+				add(name, spec.Name.Name, "field", false)
+			}
+		}
+	case *ast.InterfaceType:
+		for _, method := range t.Methods.List {
+			for _, name := range method.Names {
+				add(name, spec.Name.Name, "method", false)
+			}
+		}
+	}
+}
+
+// paramIdents returns the named parameters of every function and function
+// literal that has a body. Receivers and results are not included.
+func (g *goFile) paramIdents() []*ast.Ident {
+	var out []*ast.Ident
+	collect := func(ft *ast.FuncType, body *ast.BlockStmt) {
+		if body == nil || ft.Params == nil {
+			return
+		}
+		for _, field := range ft.Params.List {
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					out = append(out, name)
+				}
+			}
+		}
+	}
+	ast.Inspect(g.file, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			collect(fn.Type, fn.Body)
+		case *ast.FuncLit:
+			collect(fn.Type, fn.Body)
+		}
+		return true
+	})
+	return out
+}
+
+func (g *goFile) parameters() []goDecl {
+	var out []goDecl
+	for _, ident := range g.paramIdents() {
+		if obj := g.info.Defs[ident]; obj != nil {
+			out = append(out, goDecl{obj: obj, name: obj.Name(), kind: "parameter", line: g.line(ident.Pos()), local: true})
+		}
+	}
+	return out
+}
+
+func FindGoImports(content string) []GoImportItem {
+	g := checkGoFile(content, "")
+	if g == nil {
+		return nil
+	}
+	var imports []GoImportItem
+	for _, imp := range g.imports() {
+		imports = append(imports, imp.item)
+	}
+	return imports
+}
+
+func FindGoVariables(content string) []GoVariable {
+	g := checkGoFile(content, "")
+	if g == nil {
+		return nil
+	}
+	var vars []GoVariable
+	for _, d := range g.declarations() {
+		vars = append(vars, GoVariable{Name: d.name, Line: d.line, Type: d.kind})
+	}
+	return vars
+}
+
+func FindGoParameters(content string) []GoParameter {
+	g := checkGoFile(content, "")
+	if g == nil {
+		return nil
+	}
+	var params []GoParameter
+	for _, p := range g.parameters() {
+		params = append(params, GoParameter{Name: p.obj.Name(), Line: p.line})
+	}
+	return params
+}
+
+func init() {
+	RegisterLanguage(goAnalyzer{})
+}
+
+type goAnalyzer struct{}
+
+func (goAnalyzer) Language() Language { return LangGo }
+
+func (goAnalyzer) Extensions() []string { return []string{".go"} }
+
+func (goAnalyzer) Comments() CommentSyntax {
+	return CommentSyntax{Line: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+func (goAnalyzer) StandaloneDefinitions() bool { return true }
+
+func (goAnalyzer) UnusedParameterName(string) string { return "_" }
+
+// Identifiers scans code with go/scanner, which also reads files that do
+// not parse.
+func (goAnalyzer) Identifiers(code string) map[string]bool {
+	names := make(map[string]bool)
+	src := []byte(code)
+	var s scanner.Scanner
+	s.Init(gotoken.NewFileSet().AddFile("", -1, len(src)), src, nil, 0)
+	for {
+		_, tok, lit := s.Scan()
+		if tok == gotoken.EOF {
+			return names
+		}
+		if tok == gotoken.IDENT {
+			names[lit] = true
+		}
+	}
+}
+
+func (goAnalyzer) FunctionKeyword() string { return "func" }
+
+// Parse reports all package-level declarations, while local variables and
+// parameters are resolved per object here and only returned when unused:
+// names such as err or ctx are declared many times per file, so they cannot
+// be told apart by UsedLocally's name-keyed result. Declarations other
+// packages can refer to are marked Exported, which excludes everything in
+// package main and in test files.
+func (goAnalyzer) Parse(content, filename string) ParsedFile {
+	parsed := ParsedFile{Code: stripGoImports(content)}
+
+	g := checkGoFile(content, filename)
+	if g == nil {
+		return parsed
+	}
+
+	// Blank imports are never reported, but they keep a fix from deleting
+	// the import block they are part of.
+	for _, imp := range g.imports() {
+		line, column := g.position(imp.spec.Pos())
+		endLine, endColumn := g.position(imp.spec.End())
+		var stmt Span
+		stmt.Line, stmt.Column = g.position(imp.decl.Pos())
+		stmt.EndLine, stmt.EndColumn = g.position(imp.decl.End())
+		parsed.Imports = append(parsed.Imports, Import{
+			Name:      goImportKey(imp.item.alias, imp.item.path),
+			File:      filename,
+			Line:      line,
+			Column:    column,
+			EndLine:   endLine,
+			EndColumn: endColumn,
+			Source:    imp.item.path,
+			Text:      imp.item.text,
+			Statement: stmt,
+		})
+	}
+
+	importable := g.file.Name.Name != "main" && !strings.HasSuffix(filename, "_test.go")
+	for _, d := range g.declarations() {
+		if d.local && g.isUsed(d.obj) {
+			continue
+		}
+		line, column := g.position(d.obj.Pos())
+		parsed.Definitions = append(parsed.Definitions, Definition{
+			Name:     d.name,
+			File:     filename,
+			Line:     line,
+			Column:   column,
+			Type:     d.kind,
+			Exported: importable && !d.local && goExportedName(d.name),
+			Member:   d.member,
+		})
+	}
+
+	for _, p := range g.parameters() {
+		if g.isUsed(p.obj) {
+			continue
+		}
+		line, column := g.position(p.obj.Pos())
+		parsed.Parameters = append(parsed.Parameters, CodeIssue{
+			Line:      line,
+			Column:    column,
+			EndLine:   line,
+			EndColumn: column + utf16Len(p.obj.Name()),
+			Text:      "parameter " + p.obj.Name(),
+			Kind:      "parameter",
+			Symbol:    p.obj.Name(),
+			File:      filename,
+		})
+	}
+
+	return parsed
+}
+
+func (goAnalyzer) UsedLocally(content string, parsed ParsedFile) map[string]bool {
+	used := make(map[string]bool)
+
+	// The filename tells test files apart, see isTestFunction.
+	filename := ""
+	if len(parsed.Definitions) > 0 {
+		filename = parsed.Definitions[0].File
+	}
+	g := checkGoFile(content, filename)
+	if g == nil {
+		return used
+	}
+	for _, imp := range g.imports() {
+		if g.importUsed(imp) {
+			used[goImportKey(imp.item.alias, imp.item.path)] = true
+		}
+	}
+	for _, d := range g.declarations() {
+		if !d.local && g.isUsed(d.obj) {
+			used[d.name] = true
+		}
+	}
+	return used
+}
+
+// goExportedName reports whether every part of a declared name such as
+// "T.Method" is exported, so that other packages can refer to it.
+func goExportedName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !ast.IsExported(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// stripGoImports blanks single-line imports and import blocks.
+func stripGoImports(content string) string {
+	inBlock := false
+	return blankLines(content, func(trimmed string) bool {
+		if inBlock {
+			if trimmed == ")" {
+				inBlock = false
+			}
+			return true
+		}
+		if strings.HasPrefix(trimmed, "import (") {
+			inBlock = true
+			return true
+		}
+		return strings.HasPrefix(trimmed, "import ")
+	})
+}
@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// unusedGoNames returns the names of the unused definitions single-file
+// analysis reports for a Go file.
+func unusedGoNames(filename, content string) []string {
+	var names []string
+	for _, issue := range New().Analyze(AnalyzeRequest{Filename: filename, Content: content}).Variables {
+		names = append(names, issue.Symbol)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGoTestFunctionsAreUsed(t *testing.T) {
+	const tests = `package x
+
+import (
+	"testing"
+	tt "testing"
+)
+
+func TestA(t *testing.T)          {}
+func Test(t *testing.T)           {}
+func TestMain(m *testing.M)       {}
+func BenchmarkB(b *testing.B)     {}
+func FuzzF(f *testing.F)          {}
+func Example()                    {}
+func ExampleA_second()            {}
+func TestAliased(t *tt.T)         {}
+func Testlower(t *testing.T)      {}
+func TestWrongType(t *testing.B)  {}
+func TestResult(t *testing.T) int { return 0 }
+func ExampleArgs(n int)           {}
+func helper()                     {}
+`
+	for _, tt := range []struct {
+		filename string
+		want     []string
+	}{
+		{"x_test.go", []string{"ExampleArgs", "TestResult", "TestWrongType", "Testlower", "helper"}},
+		{"x.go", []string{"BenchmarkB", "Example", "ExampleA_second", "ExampleArgs", "FuzzF", "Test", "TestA", "TestAliased", "TestMain", "TestResult", "TestWrongType", "Testlower", "helper"}},
+	} {
+		if got := unusedGoNames(tt.filename, tests); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: unused %v, want %v", tt.filename, got, tt.want)
+		}
+	}
+
+	// Workspace analysis resolves usage per package and must agree.
+	files := []AnalyzeFile{
+		{Filename: "go.mod", Content: "module example.com/x\n"},
+		{Filename: "x.go", Content: "package x\n\nfunc Helper() int { return 1 }\n"},
+		{Filename: "x_test.go", Content: "package x\n\nimport \"testing\"\n\nfunc TestHelper(t *testing.T) {\n\tif Helper() != 1 {\n\t\tt.Fatal()\n\t}\n}\n\nfunc unused() {}\n"},
+	}
+	var got []string
+	for _, issue := range New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: files}).Results["x_test.go"].Variables {
+		got = append(got, issue.Symbol)
+	}
+	if want := []string{"unused"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("workspace: unused %v in x_test.go, want %v", got, want)
+	}
+}
+
+// goFindings lists the findings of single-file analysis of a Go file as
+// "kind symbol:line", imports first.
+func goFindings(content string) []string {
+	res := New().Analyze(AnalyzeRequest{Filename: "x.go", Content: content})
+	var out []string
+	for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters} {
+		for _, issue := range issues {
+			out = append(out, fmt.Sprintf("%s %s:%d", issue.Kind, issue.Symbol, issue.Line))
+		}
+	}
+	return out
+}
+
+func TestGoResolvesScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "imports used through selectors and aliases",
+			content: "package main\n\nimport (\n\t\"fmt\"\n\tstr \"strings\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println(str.ToUpper(\"\"))\n}\n",
+			want:    []string{"import os:6"},
+		},
+		{
+			name:    "types in parameter lists are not parameters",
+			content: "package main\n\nimport \"io\"\n\ntype conf struct{}\n\nfunc run(r io.Reader, c conf, n int) int {\n\treturn n\n}\n\nfunc main() { run(nil, conf{}, 1) }\n",
+			want:    []string{"parameter r:7", "parameter c:7"},
+		},
+		{
+			name:    "shadowed variables are resolved per scope",
+			content: "package main\n\nfunc main() {\n\tx := 1\n\tif x := 2; x > 1 {\n\t\treturn\n\t}\n}\n",
+			want:    []string{"variable x:4"},
+		},
+		{
+			name:    "a parameter used only in a closure is used",
+			content: "package main\n\nfunc run(n int) func() int {\n\treturn func() int { return n }\n}\n\nfunc main() { run(1) }\n",
+			want:    nil,
+		},
+		{
+			name:    "an unparsable file reports nothing",
+			content: "package main\n\nfunc main( {\n",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		if got := goFindings(tt.content); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGoMembersAndGroupedDeclarations(t *testing.T) {
+	const content = `package main
+
+import "fmt"
+
+type T struct {
+	used   int
+	unused int
+	Tagged string ` + "`json:\"tagged\"`" + `
+}
+
+type P struct{ a, b int }
+
+type S interface{ Do() }
+
+type impl struct{}
+
+func (impl) Do()              {}
+func (impl) String() string   { return "" }
+func (*impl) extra()          {}
+
+const (
+	A = iota
+	B
+	C
+)
+
+var (
+	x = 1
+	y = 2
+)
+
+func main() {
+	t := T{used: 1}
+	_ = P{1, 2}
+	var s S = impl{}
+	s.Do()
+	fmt.Println(t.used, A, x)
+}
+`
+	want := []string{
+		"field T.unused:7",
+		"method impl.extra:19",
+		"constant B:23",
+		"constant C:24",
+		"variable y:29",
+	}
+	if got := goFindings(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Members of another package are used through selectors.
+	files := []AnalyzeFile{
+		{Filename: "go.mod", Content: "module example.com/m\n"},
+		{Filename: "lib/lib.go", Content: "package lib\n\ntype Conf struct {\n\tName string\n\tPort int\n}\n\nfunc (c Conf) Addr() string { return c.Name }\n\nfunc (c Conf) Reset() {}\n"},
+		{Filename: "main.go", Content: "package main\n\nimport \"example.com/m/lib\"\n\nfunc main() {\n\tprintln(lib.Conf{Name: \"x\"}.Addr())\n}\n"},
+	}
+	var got []string
+	for _, issue := range New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: files}).Results["lib/lib.go"].Variables {
+		got = append(got, issue.Symbol)
+	}
+	if want := []string{"Conf.Port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("workspace: unused %v in lib/lib.go, want %v", got, want)
+	}
+}
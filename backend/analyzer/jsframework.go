@@ -0,0 +1,948 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Astro file parsing (frontmatter between ---). Lines before the
+// frontmatter are kept empty so that line numbers match the file.
+func extractAstroScript(content string) string {
+	lines := strings.Split(content, "\n")
+	var inFrontmatter bool
+	var frontmatterLines []string
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Start of frontmatter
+		if trimmed == "---" && !inFrontmatter {
+			inFrontmatter = true
+			frontmatterLines = append(frontmatterLines, "")
+			continue
+		}
+
+		// End of frontmatter
+		if trimmed == "---" && inFrontmatter {
+			return strings.Join(frontmatterLines, "\n")
+		}
+
+		if inFrontmatter {
+			frontmatterLines = append(frontmatterLines, line)
+		} else {
+			frontmatterLines = append(frontmatterLines, "")
+		}
+	}
+
+	if !inFrontmatter {
+		return ""
+	}
+	return strings.Join(frontmatterLines, "\n")
+}
+
+// Svelte/Vue file parsing (script tags)
+func extractScriptContent(content string) string {
+	var result strings.Builder
+	var inScript bool
+	var inScriptTag bool
+
+	for i := 0; i < len(content); i++ {
+		// Look for <script
+		if !inScript && i+7 < len(content) {
+			chunk := strings.ToLower(content[i : i+7])
+			if strings.HasPrefix(chunk, "<script") {
+				// Check if it's a script tag (not <script something else)
+				nextChar := content[i+7]
+				if nextChar == '>' || nextChar == ' ' || nextChar == '\t' || nextChar == '\n' {
+					inScriptTag = true
+					i += 6
+					continue
+				}
+			}
+		}
+
+		// Find end of script tag
+		if inScriptTag && !inScript {
+			if content[i] == '>' {
+				inScript = true
+				inScriptTag = false
+				continue
+			}
+		}
+
+		// Look for </script>
+		if inScript && i+8 < len(content) {
+			chunk := strings.ToLower(content[i : i+9])
+			if chunk == "</script>" {
+				inScript = false
+				i += 8
+				continue
+			}
+		}
+
+		// Newlines outside of scripts are kept so that line numbers in
+		// the extracted script match the file.
+		if inScript || content[i] == '\n' {
+			result.WriteByte(content[i])
+		}
+	}
+
+	return result.String()
+}
+
+// Tokenizer-based JS/TS analysis for extracted script content
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdentifier
+	tokImport
+	tokFrom
+	tokString
+	tokLeftBrace
+	tokRightBrace
+	tokStar
+	tokComma
+	tokAs
+	tokConst
+	tokLet
+	tokVar
+	tokFunction
+	tokExport
+	tokDefault
+	tokType
+	tokInterface
+	tokOther
+)
+
+// token is a JS token; col is the 1-based UTF-16 column where it starts.
+type token struct {
+	typ  tokenType
+	val  string
+	line int
+	col  int
+}
+
+// Simple tokenizer for JS/TS script content
+func tokenizeJS(content string) []token {
+	var tokens []token
+	lines := strings.Split(content, "\n")
+
+	for lineNum, line := range lines {
+		i := 0
+		for i < len(line) {
+			// Skip whitespace
+			for i < len(line) && unicode.IsSpace(rune(line[i])) {
+				i++
+			}
+			if i >= len(line) {
+				break
+			}
+
+			// Skip comments
+			if i+1 < len(line) && line[i] == '/' && line[i+1] == '/' {
+				break // Skip rest of line
+			}
+			if i+1 < len(line) && line[i] == '/' && line[i+1] == '*' {
+				// Skip multi-line comment
+				for i < len(line) && !(line[i-1] == '*' && line[i] == '/') {
+					i++
+				}
+				i++
+				continue
+			}
+
+			// String literals
+			if line[i] == '"' || line[i] == '\'' || line[i] == '`' {
+				quote := line[i]
+				start := i
+				i++
+				for i < len(line) && line[i] != quote {
+					if line[i] == '\\' && i+1 < len(line) {
+						i += 2
+					} else {
+						i++
+					}
+				}
+				if i < len(line) {
+					i++
+				}
+				tokens = append(tokens, token{typ: tokString, val: line[start:i], line: lineNum + 1, col: utf16Column(line, start)})
+				continue
+			}
+
+			// Identifiers and keywords
+			if isJSIdentStart(rune(line[i])) {
+				start := i
+				for i < len(line) && isJSIdentPart(rune(line[i])) {
+					i++
+				}
+				ident := line[start:i]
+				tok := token{typ: tokIdentifier, val: ident, line: lineNum + 1, col: utf16Column(line, start)}
+
+				switch ident {
+				case "import":
+					tok.typ = tokImport
+				case "from":
+					tok.typ = tokFrom
+				case "as":
+					tok.typ = tokAs
+				case "const":
+					tok.typ = tokConst
+				case "let":
+					tok.typ = tokLet
+				case "var":
+					tok.typ = tokVar
+				case "function":
+					tok.typ = tokFunction
+				case "export":
+					tok.typ = tokExport
+				case "default":
+					tok.typ = tokDefault
+				case "type":
+					tok.typ = tokType
+				case "interface":
+					tok.typ = tokInterface
+				}
+
+				tokens = append(tokens, tok)
+				continue
+			}
+
+			// Single character tokens
+			col := utf16Column(line, i)
+			switch line[i] {
+			case '{':
+				tokens = append(tokens, token{typ: tokLeftBrace, val: "{", line: lineNum + 1, col: col})
+			case '}':
+				tokens = append(tokens, token{typ: tokRightBrace, val: "}", line: lineNum + 1, col: col})
+			case '*':
+				tokens = append(tokens, token{typ: tokStar, val: "*", line: lineNum + 1, col: col})
+			case ',':
+				tokens = append(tokens, token{typ: tokComma, val: ",", line: lineNum + 1, col: col})
+			case ';':
+				// Statement separator
+			default:
+				tokens = append(tokens, token{typ: tokOther, val: string(line[i]), line: lineNum + 1, col: col})
+			}
+			i++
+		}
+	}
+
+	tokens = append(tokens, token{typ: tokEOF, line: len(lines)})
+	return tokens
+}
+
+func isJSIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isJSIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+// Parse imports from tokens. Every name bound by a statement is an import
+// spanning from its first token to its alias, e.g. "* as ns" or "a as b",
+// with the statement running from the import keyword to the source string.
+func parseJSImports(tokens []token) []Import {
+	var imports []Import
+	i := 0
+
+	for i < len(tokens) {
+		if tokens[i].typ != tokImport {
+			i++
+			continue
+		}
+
+		importTok := tokens[i]
+		var stmt []Import
+		i++
+
+		// Skip whitespace and comments in parsing
+		for i < len(tokens) && (tokens[i].typ == tokOther || tokens[i].typ == tokEOF) {
+			i++
+		}
+
+		if i >= len(tokens) {
+			break
+		}
+
+		// import * as name from "..."
+		if tokens[i].typ == tokStar {
+			star := tokens[i]
+			i++
+			// Skip "as"
+			if i < len(tokens) && tokens[i].typ == tokAs {
+				i++
+			}
+			// Get namespace name
+			if i < len(tokens) && tokens[i].typ == tokIdentifier {
+				stmt = append(stmt, jsImport(star, tokens[i]))
+				i++
+			}
+		}
+
+		// import name from "..."
+		if i < len(tokens) && tokens[i].typ == tokIdentifier {
+			stmt = append(stmt, jsImport(tokens[i], tokens[i]))
+			i++
+
+			// Check for named imports: import name, { ... } from "..."
+			if i < len(tokens) && tokens[i].typ == tokComma {
+				i++
+				// Continue to parse { ... } or * as name
+				if i < len(tokens) && tokens[i].typ == tokStar {
+					star := tokens[i]
+					i++
+					if i < len(tokens) && tokens[i].typ == tokAs {
+						i++
+					}
+					if i < len(tokens) && tokens[i].typ == tokIdentifier {
+						stmt = append(stmt, jsImport(star, tokens[i]))
+						i++
+					}
+				}
+			}
+		}
+
+		// import { a, b as c, type d } from "..."
+		if i < len(tokens) && tokens[i].typ == tokLeftBrace {
+			i++
+			for i < len(tokens) && tokens[i].typ != tokRightBrace && tokens[i].typ != tokEOF {
+				start := tokens[i]
+				if start.typ == tokType && i+1 < len(tokens) && tokens[i+1].typ == tokIdentifier {
+					i++
+				}
+				if tokens[i].typ != tokIdentifier && tokens[i].typ != tokDefault {
+					i++
+					continue
+				}
+				if start.typ != tokType {
+					start = tokens[i]
+				}
+				name := tokens[i]
+				i++
+				if i+1 < len(tokens) && tokens[i].typ == tokAs && tokens[i+1].typ == tokIdentifier {
+					name = tokens[i+1]
+					i += 2
+				}
+				if name.typ == tokIdentifier {
+					stmt = append(stmt, jsImport(start, name))
+				}
+			}
+			if i < len(tokens) && tokens[i].typ == tokRightBrace {
+				i++
+			}
+		}
+
+		// Skip "from" and source
+		for i < len(tokens) && tokens[i].typ != tokString && tokens[i].typ != tokEOF {
+			i++
+		}
+		if i < len(tokens) && tokens[i].typ == tokString {
+			source := tokens[i]
+			span := Span{
+				Line:      importTok.line,
+				Column:    importTok.col,
+				EndLine:   source.line,
+				EndColumn: source.col + utf16Len(source.val),
+			}
+			for j := range stmt {
+				stmt[j].Source = strings.Trim(source.val, "\"'`")
+				stmt[j].Statement = span
+			}
+			i++
+		}
+		imports = append(imports, stmt...)
+	}
+
+	return imports
+}
+
+// jsImport is the import of the name bound by the tokens from start to
+// name.
+func jsImport(start, name token) Import {
+	return Import{
+		Name:      name.val,
+		Line:      start.line,
+		Column:    start.col,
+		EndLine:   name.line,
+		EndColumn: name.col + utf16Len(name.val),
+	}
+}
+
+// Parse definitions (variables, functions, types, interfaces) from tokens.
+// Declarations following export, and names listed by an export clause
+// without a source, are marked Exported.
+func parseJSDefinitions(tokens []token, filename string) []Definition {
+	var defs []Definition
+	listed := jsExportList(tokens)
+	i := 0
+
+	for i < len(tokens) {
+		first := len(defs)
+		exported := jsExported(tokens, i)
+		switch tokens[i].typ {
+		case tokConst, tokLet, tokVar:
+			i++
+			// Handle destructuring: const { a, b } = ...
+			if i < len(tokens) && tokens[i].typ == tokLeftBrace {
+				i++
+				for i < len(tokens) && tokens[i].typ != tokRightBrace && tokens[i].typ != tokEOF {
+					if tokens[i].typ == tokIdentifier {
+						defs = append(defs, Definition{
+							Name:   tokens[i].val,
+							File:   filename,
+							Line:   tokens[i].line,
+							Column: tokens[i].col,
+							Type:   "variable",
+						})
+					}
+					i++
+				}
+				if i < len(tokens) && tokens[i].typ == tokRightBrace {
+					i++
+				}
+			} else if i < len(tokens) && tokens[i].typ == tokIdentifier {
+				// Simple declaration: const name = ...
+				defs = append(defs, Definition{
+					Name:   tokens[i].val,
+					File:   filename,
+					Line:   tokens[i].line,
+					Column: tokens[i].col,
+					Type:   "variable",
+				})
+				i++
+			}
+
+		case tokFunction:
+			i++
+			if i < len(tokens) && tokens[i].typ == tokIdentifier {
+				defs = append(defs, Definition{
+					Name:   tokens[i].val,
+					File:   filename,
+					Line:   tokens[i].line,
+					Column: tokens[i].col,
+					Type:   "function",
+				})
+				i++
+			}
+
+		case tokType:
+			i++
+			if i < len(tokens) && tokens[i].typ == tokIdentifier {
+				defs = append(defs, Definition{
+					Name:   tokens[i].val,
+					File:   filename,
+					Line:   tokens[i].line,
+					Column: tokens[i].col,
+					Type:   "type",
+				})
+				i++
+			}
+
+		case tokInterface:
+			i++
+			if i < len(tokens) && tokens[i].typ == tokIdentifier {
+				defs = append(defs, Definition{
+					Name:   tokens[i].val,
+					File:   filename,
+					Line:   tokens[i].line,
+					Column: tokens[i].col,
+					Type:   "interface",
+				})
+				i++
+			}
+
+		default:
+			i++
+		}
+		for j := first; j < len(defs); j++ {
+			defs[j].Exported = exported || listed[defs[j].Name]
+		}
+	}
+
+	return defs
+}
+
+// jsExported reports whether the declaration keyword at tokens[i] is
+// preceded by export, possibly with default or modifiers in between.
+func jsExported(tokens []token, i int) bool {
+	for i--; i >= 0; i-- {
+		switch {
+		case tokens[i].typ == tokExport:
+			return true
+		case tokens[i].typ == tokDefault,
+			tokens[i].typ == tokIdentifier && (tokens[i].val == "async" || tokens[i].val == "declare" || tokens[i].val == "abstract"):
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// jsExportList returns the local names of "export { a, b as c }" clauses;
+// clauses with a from re-export names of other modules.
+func jsExportList(tokens []token) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].typ != tokExport || tokens[i+1].typ != tokLeftBrace {
+			continue
+		}
+		var clause []string
+		j := i + 2
+		for ; j < len(tokens) && tokens[j].typ != tokRightBrace && tokens[j].typ != tokEOF; j++ {
+			if tokens[j].typ == tokIdentifier && tokens[j-1].typ != tokAs {
+				clause = append(clause, tokens[j].val)
+			}
+		}
+		if j+1 < len(tokens) && tokens[j+1].typ == tokFrom {
+			continue
+		}
+		for _, name := range clause {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Check if Astro component is used in template
+func isAstroComponentUsedInTemplate(content string, componentName string) bool {
+	// Split at end of frontmatter
+	parts := strings.Split(content, "---")
+	if len(parts) < 3 {
+		return false
+	}
+
+	// Template is after second ---
+	template := parts[2]
+
+	// Check for component usage: <ComponentName or ComponentName in template
+	lowerComponent := strings.ToLower(componentName)
+	lowerTemplate := strings.ToLower(template)
+
+	// Direct usage: <ComponentName
+	if strings.Contains(lowerTemplate, "<"+lowerComponent) {
+		return true
+	}
+
+	// In expressions: {ComponentName}
+	if strings.Contains(lowerTemplate, "{"+componentName) {
+		return true
+	}
+
+	return false
+}
+
+// Check if Svelte variable is used in template
+func isSvelteVariableUsedInTemplate(content string, varName string) bool {
+	// Extract template (outside <script> tags)
+	template := removeScriptTags(content)
+
+	// Check for variable usage in template
+	return containsWord(template, varName)
+}
+
+func removeScriptTags(content string) string {
+	var result strings.Builder
+	var inScript bool
+	var inScriptTag bool
+	i := 0
+
+	for i < len(content) {
+		if !inScript && i+7 < len(content) {
+			chunk := strings.ToLower(content[i : i+7])
+			if strings.HasPrefix(chunk, "<script") {
+				nextChar := content[i+7]
+				if nextChar == '>' || nextChar == ' ' || nextChar == '\t' || nextChar == '\n' {
+					inScriptTag = true
+					i += 6
+					continue
+				}
+			}
+		}
+
+		if inScriptTag && !inScript {
+			if content[i] == '>' {
+				inScript = true
+				inScriptTag = false
+				i++
+				continue
+			}
+		}
+
+		if inScript && i+8 < len(content) {
+			chunk := strings.ToLower(content[i : i+9])
+			if chunk == "</script>" {
+				inScript = false
+				i += 9
+				continue
+			}
+		}
+
+		if !inScript && !inScriptTag {
+			result.WriteByte(content[i])
+		}
+		i++
+	}
+
+	return result.String()
+}
+
+func init() {
+	RegisterLanguage(jsFrameworkAnalyzer{
+		lang:           LangAstro,
+		extensions:     []string{".astro"},
+		extractScript:  extractAstroScript,
+		usedInTemplate: isAstroComponentUsedInTemplate,
+	})
+	RegisterLanguage(jsFrameworkAnalyzer{
+		lang:           LangSvelte,
+		extensions:     []string{".svelte"},
+		extractScript:  extractScriptContent,
+		usedInTemplate: isSvelteVariableUsedInTemplate,
+	})
+	// Vue uses same script extraction as Svelte
+	RegisterLanguage(jsFrameworkAnalyzer{
+		lang:           LangVue,
+		extensions:     []string{".vue"},
+		extractScript:  extractScriptContent,
+		usedInTemplate: isSvelteVariableUsedInTemplate,
+	})
+}
+
+// jsFrameworkAnalyzer analyzes the script part of component files. Names
+// referenced only from the template count as used.
+type jsFrameworkAnalyzer struct {
+	lang           Language
+	extensions     []string
+	extractScript  func(content string) string
+	usedInTemplate func(content, name string) bool
+}
+
+func (a jsFrameworkAnalyzer) Language() Language { return a.lang }
+
+func (a jsFrameworkAnalyzer) Extensions() []string { return a.extensions }
+
+func (jsFrameworkAnalyzer) Comments() CommentSyntax {
+	return CommentSyntax{Line: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+func (jsFrameworkAnalyzer) StandaloneDefinitions() bool { return true }
+
+// FileScoped is true: names of the script are used by the markup of the
+// same component, and a word in another file is not a use of them.
+func (jsFrameworkAnalyzer) FileScoped() bool { return true }
+
+func (jsFrameworkAnalyzer) UnusedParameterName(name string) string { return "_" + name }
+
+// FunctionKeyword is empty: templates call script functions from markup,
+// where calls cannot be rewritten, so parameters are only renamed.
+func (jsFrameworkAnalyzer) FunctionKeyword() string { return "" }
+
+func (a jsFrameworkAnalyzer) Parse(content, filename string) ParsedFile {
+	parsed := ParsedFile{
+		Code: blankLines(content, func(trimmed string) bool {
+			return strings.HasPrefix(trimmed, "import ") ||
+				(strings.HasPrefix(trimmed, "export ") && strings.Contains(trimmed, "from"))
+		}),
+	}
+
+	scriptContent := a.extractScript(content)
+	if strings.TrimSpace(scriptContent) == "" {
+		return parsed
+	}
+
+	tokens := tokenizeJS(scriptContent)
+	parsed.Imports = parseJSImports(tokens)
+	for i := range parsed.Imports {
+		parsed.Imports[i].File = filename
+	}
+	parsed.Definitions = parseJSDefinitions(tokens, filename)
+	parsed.Parameters = findJSUnusedParameters(scriptContent, filename)
+	return parsed
+}
+
+// jsComponentExtensions are tried for import sources without extension.
+var jsComponentExtensions = []string{".astro", ".svelte", ".vue"}
+
+// IsTestFile reports whether filename is named as a test or spec of
+// Vitest and Jest, such as Button.test.vue or Button.spec.svelte.
+func (jsFrameworkAnalyzer) IsTestFile(filename string) bool {
+	base := path.Base(slashPath(filename))
+	return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.")
+}
+
+// ResolveImports resolves relative sources against the importing file,
+// and the "$lib/" alias of SvelteKit and the "@/" and "~/" aliases of Vue
+// and Nuxt against any src directory. Sources without a file of their own
+// are tried with the component extensions and as index files.
+func (jsFrameworkAnalyzer) ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string {
+	dir := path.Dir(indexPath(file.Filename))
+	var deps []string
+	for _, imp := range parsed.Imports {
+		source := imp.Source
+		switch {
+		case strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../"):
+			deps = append(deps, resolveRelative(ix, dir, source, jsComponentExtensions)...)
+		case strings.HasPrefix(source, "$lib/"):
+			deps = append(deps, findWithExtensions(ix, "src/lib/"+strings.TrimPrefix(source, "$lib/"), jsComponentExtensions)...)
+		case strings.HasPrefix(source, "@/") || strings.HasPrefix(source, "~/"):
+			deps = append(deps, findWithExtensions(ix, "src/"+source[2:], jsComponentExtensions)...)
+		}
+	}
+	return deps
+}
+
+// UsedLocally counts identifier occurrences in the script, where the
+// declaration itself accounts for one, and falls back to the template.
+// Parameters are already filtered by findJSUnusedParameters and are never
+// marked as used here.
+func (a jsFrameworkAnalyzer) UsedLocally(content string, parsed ParsedFile) map[string]bool {
+	counts := make(map[string]int)
+	for _, tok := range tokenizeJS(a.extractScript(content)) {
+		if tok.typ == tokIdentifier {
+			counts[tok.val]++
+		}
+	}
+
+	used := make(map[string]bool)
+	check := func(name string) {
+		if counts[name] > 1 || a.usedInTemplate(content, name) {
+			used[name] = true
+		}
+	}
+	for _, imp := range parsed.Imports {
+		check(imp.Name)
+	}
+	for _, def := range parsed.Definitions {
+		check(def.Name)
+	}
+	return used
+}
+
+// FindReferences returns the identifiers of the script UsedLocally counts,
+// besides the declaration, and the occurrences of name on template lines
+// when usedInTemplate finds it there.
+func (a jsFrameworkAnalyzer) FindReferences(_ []AnalyzeFile, file AnalyzeFile, parsed ParsedFile, name string) []Reference {
+	script := a.extractScript(file.Content)
+	source := strings.Split(file.Content, "\n")
+	reference := func(line, column int, rule string) Reference {
+		ref := Reference{File: file.Filename, Span: Span{Line: line, Column: column, EndLine: line, EndColumn: column + utf16Len(name)}, Rule: rule}
+		if line >= 1 && line <= len(source) {
+			ref.Text = strings.TrimSpace(source[line-1])
+		}
+		return ref
+	}
+
+	var refs []Reference
+	decl, _ := findDeclaration(parsed, name)
+	declares := decl.declares(script, name)
+	count, skipped := 0, false
+	for _, tok := range tokenizeJS(script) {
+		if tok.typ != tokIdentifier || tok.val != name {
+			continue
+		}
+		count++
+		if declares(tok.line, tok.col) {
+			skipped = true
+			continue
+		}
+		refs = append(refs, reference(tok.line, tok.col, RuleLocal))
+	}
+	switch {
+	case count < 2:
+		refs = nil
+	case !skipped:
+		refs = refs[1:]
+	}
+
+	if !a.usedInTemplate(file.Content, name) {
+		return refs
+	}
+	located := false
+	scriptLines := strings.Split(script, "\n")
+	for i, text := range source {
+		if i < len(scriptLines) && strings.TrimSpace(scriptLines[i]) != "" {
+			continue
+		}
+		for offset := 0; offset < len(text); {
+			pos := indexWordInLine(text[offset:], name)
+			if pos < 0 {
+				break
+			}
+			pos += offset
+			offset = pos + len(name)
+			refs = append(refs, reference(i+1, utf16Column(text, pos), RuleTemplate))
+			located = true
+		}
+	}
+	if !located {
+		refs = append(refs, Reference{File: file.Filename, Rule: RuleTemplate, Text: "matched by the template check"})
+	}
+	return refs
+}
+
+// Find unused parameters in JS/TS code
+func findJSUnusedParameters(content string, filename string) []CodeIssue {
+	lines := strings.Split(content, "\n")
+	var issues []CodeIssue
+
+	for lineNum, line := range lines {
+		// Look for function declarations
+		trimmed := strings.TrimSpace(line)
+
+		// Match: function name(...)
+		if strings.HasPrefix(trimmed, "function ") {
+			// Extract parameters from function declaration
+			start := strings.Index(trimmed, "(")
+			end := strings.Index(trimmed, ")")
+			if start != -1 && end != -1 && end > start {
+				paramsStr := trimmed[start+1 : end]
+				params := parseJSParams(paramsStr)
+
+				// Check each parameter usage in function body
+				for _, param := range params {
+					if !isParamUsedInFunction(lines, lineNum, param) {
+						issues = append(issues, jsParameterIssue(line, lineNum, start, param, filename))
+					}
+				}
+			}
+		}
+
+		// Match: const fn = (...) =>
+		// Match: const fn = function(...)
+		if strings.Contains(trimmed, "=") {
+			if strings.Contains(trimmed, "=>") || strings.Contains(trimmed, "function(") {
+				start := strings.Index(trimmed, "(")
+				end := strings.Index(trimmed, ")")
+				if start != -1 && end != -1 && end > start {
+					paramsStr := trimmed[start+1 : end]
+					params := parseJSParams(paramsStr)
+
+					for _, param := range params {
+						if !isParamUsedInFunction(lines, lineNum, param) {
+							issues = append(issues, jsParameterIssue(line, lineNum, start, param, filename))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// jsParameterIssue reports param, searched for in line after the opening
+// parenthesis at paren in the trimmed line.
+func jsParameterIssue(line string, lineNum, paren int, param, filename string) CodeIssue {
+	issue := CodeIssue{
+		Line:       lineNum + 1,
+		Text:       "parameter " + param,
+		File:       filename,
+		Kind:       "parameter",
+		Symbol:     param,
+		Confidence: ConfidenceLow,
+	}
+	offset := strings.Index(line, strings.TrimSpace(line)) + paren
+	if pos := indexWordInLine(line[offset:], param); pos >= 0 {
+		issue.Column = utf16Column(line, offset+pos)
+		issue.EndLine = issue.Line
+		issue.EndColumn = issue.Column + utf16Len(param)
+	}
+	return issue
+}
+
+// Parse JS parameters from string like "a, b, c" or "a: Type, b: Type"
+func parseJSParams(paramsStr string) []string {
+	var params []string
+	// Remove type annotations
+	paramsStr = removeJSTypes(paramsStr)
+
+	parts := strings.Split(paramsStr, ",")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Handle destructuring by skipping
+		if strings.HasPrefix(part, "{") || strings.HasPrefix(part, "[") {
+			continue
+		}
+
+		// Get parameter name (before any type annotation or default)
+		if idx := strings.IndexAny(part, ":="); idx != -1 {
+			part = strings.TrimSpace(part[:idx])
+		}
+		part = strings.TrimSuffix(strings.TrimPrefix(part, "..."), "?")
+
+		if isIdentifier(part) {
+			params = append(params, part)
+		}
+	}
+
+	return params
+}
+
+// Remove TypeScript type annotations from a string
+func removeJSTypes(s string) string {
+	var result strings.Builder
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '<' {
+			depth++
+			continue
+		}
+		if s[i] == '>' {
+			depth--
+			continue
+		}
+		if depth == 0 {
+			result.WriteByte(s[i])
+		}
+	}
+
+	return result.String()
+}
+
+// Check if a parameter is used in function body (simple version)
+func isParamUsedInFunction(lines []string, funcStartLine int, param string) bool {
+	// Find function body
+	braceDepth := 0
+	inFunction := false
+
+	for i := funcStartLine; i < len(lines); i++ {
+		line := lines[i]
+
+		if !inFunction {
+			// Look for opening brace
+			if strings.Contains(line, "{") {
+				inFunction = true
+				braceDepth = 1
+			}
+			continue
+		}
+
+		// Count braces
+		for _, ch := range line {
+			if ch == '{' {
+				braceDepth++
+			} else if ch == '}' {
+				braceDepth--
+				if braceDepth == 0 {
+					return false
+				}
+			}
+		}
+
+		// Check for parameter usage (not on the definition line)
+		if i > funcStartLine && containsWord(line, param) {
+			return true
+		}
+	}
+
+	return false
+}
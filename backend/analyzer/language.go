@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LanguageAnalyzer implements parsing and local usage checks for one
+// language. Languages register with RegisterLanguage, usually from an init
+// function next to their tokenizer, and MultiLangAnalyzer only drives them
+// through this interface and the optional ones below, so that single-file
+// and workspace analysis share one implementation. Implementations must be
+// safe for concurrent use.
+type LanguageAnalyzer interface {
+	// Language is the identifier returned by DetectLanguage.
+	Language() Language
+	// Extensions lists the lower-case file extensions handled, with the dot.
+	Extensions() []string
+	// Comments describes how comments are written, so that other files'
+	// comments are not mistaken for references.
+	Comments() CommentSyntax
+	// Parse extracts the imports, definitions and parameters of a file.
+	Parse(content, filename string) ParsedFile
+	// UsedLocally reports which of the parsed names are referenced in the
+	// same file outside of their own declaration.
+	UsedLocally(content string, parsed ParsedFile) map[string]bool
+	// StandaloneDefinitions reports whether single-file analysis should
+	// report unused definitions. Languages whose definitions are normally
+	// consumed by other files return false.
+	StandaloneDefinitions() bool
+}
+
+// WorkspaceResolver is an optional interface for languages that can decide
+// cross-file usage more precisely than the default search for the name in
+// every other file. ResolveWorkspace receives all files of the workspace,
+// including ones of other languages or without one, and returns
+// "name@filename" keys for the definitions of its own files that are used
+// from elsewhere. Files of a resolving language are not word-searched.
+type WorkspaceResolver interface {
+	ResolveWorkspace(files []AnalyzeFile) map[string]bool
+}
+
+// FileScoped is an optional interface for languages whose files are
+// analyzed on their own in a workspace too, such as components, whose
+// script names are used by their own markup. When FileScoped returns true
+// no name of such a file counts as used from another file, but its code is
+// still searched for the names of other files. Unused exports are decided
+// across files all the same, see exports.go.
+type FileScoped interface {
+	FileScoped() bool
+}
+
+// ExportResolver is an optional interface for WorkspaceResolver languages,
+// whose files may use each other's definitions without importing them,
+// such as the files of a Go package. ResolveExports returns "name@filename"
+// keys for the exported definitions of its own files that are used from
+// outside of that scope. Exports of the other languages count as used when
+// their name occurs in any other file, see exports.go.
+type ExportResolver interface {
+	ResolveExports(files []AnalyzeFile) map[string]bool
+}
+
+// ImportResolver is an optional interface for languages whose imports can
+// be resolved to files, see deadfiles.go. ResolveImports returns the files
+// of ix that file, parsed as parsed, depends on. Sources that name no file
+// of the workspace, such as third-party packages, are left out.
+type ImportResolver interface {
+	ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string
+}
+
+// TestFileDetector is an optional interface for ImportResolver languages
+// whose tests are run by a test runner rather than imported. IsTestFile
+// reports whether filename is named as such a test file, which is then an
+// implicit entry point, see deadfiles.go.
+type TestFileDetector interface {
+	IsTestFile(filename string) bool
+}
+
+// ParameterFixer is an optional interface for languages that can fix
+// unused parameters, see paramfix.go. UnusedParameterName returns the name
+// that marks parameter name as intentionally unused, or "" when the
+// language has no such idiom. FunctionKeyword is the keyword introducing
+// function declarations, or "" when parameters should never be removed
+// from a function together with the arguments passed for them.
+type ParameterFixer interface {
+	UnusedParameterName(name string) string
+	FunctionKeyword() string
+}
+
+// IndentedBlocks is an optional interface for languages whose blocks are
+// delimited by indentation and must not be empty, such as Python.
+// EmptyStatement returns the statement that replaces the only statement of
+// a block when a fix removes it, such as "pass".
+type IndentedBlocks interface {
+	EmptyStatement() string
+}
+
+// ReferenceFinder is an optional interface for languages that can locate
+// the references their usage checks count, see Explain. FindReferences
+// returns the references to name, which file declares, in file and, for a
+// WorkspaceResolver, in the other files of the workspace. Languages
+// without it are explained by searching for the name as a whole word.
+type ReferenceFinder interface {
+	FindReferences(files []AnalyzeFile, file AnalyzeFile, parsed ParsedFile, name string) []Reference
+}
+
+// IdentifierTokenizer is an optional interface for languages with a
+// tokenizer. Identifiers returns the identifiers Code refers to, leaving
+// out keywords and strings; they make up the workspace's index of which
+// file uses which name. The index holds every word of the Code of other
+// languages, see codeWords.
+type IdentifierTokenizer interface {
+	Identifiers(code string) map[string]bool
+}
+
+// ParsedFile is the language-independent outcome of LanguageAnalyzer.Parse.
+type ParsedFile struct {
+	Definitions []Definition `json:"definitions"`
+	Imports     []Import     `json:"imports"`
+	Parameters  []CodeIssue  `json:"parameters"`
+	// Code is the content other files are searched in for references, with
+	// import statements blanked out so that importing a name is not usage.
+	// Line numbers must match the original content.
+	Code string `json:"code"`
+}
+
+// CommentSyntax lists the comment markers of a language.
+type CommentSyntax struct {
+	Line       []string
+	BlockStart string
+	BlockEnd   string
+}
+
+var registry = struct {
+	sync.RWMutex
+	byLang map[Language]LanguageAnalyzer
+	byExt  map[string]LanguageAnalyzer
+}{
+	byLang: make(map[Language]LanguageAnalyzer),
+	byExt:  make(map[string]LanguageAnalyzer),
+}
+
+// RegisterLanguage makes la available to DetectLanguage and every
+// MultiLangAnalyzer. Registering a language or extension that is already
+// known replaces the previous analyzer, which allows built-in languages to
+// be overridden.
+func RegisterLanguage(la LanguageAnalyzer) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.byLang[la.Language()] = la
+	for _, ext := range la.Extensions() {
+		registry.byExt[strings.ToLower(ext)] = la
+	}
+}
+
+// LookupLanguage returns the analyzer registered for lang.
+func LookupLanguage(lang Language) (LanguageAnalyzer, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	la, ok := registry.byLang[lang]
+	return la, ok
+}
+
+// Languages returns the registered languages in alphabetical order.
+func Languages() []Language {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	langs := make([]Language, 0, len(registry.byLang))
+	for lang := range registry.byLang {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+func languageFor(filename string) LanguageAnalyzer {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.byExt[strings.ToLower(filepath.Ext(filename))]
+}
+
+func DetectLanguage(filename string) Language {
+	if la := languageFor(filename); la != nil {
+		return la.Language()
+	}
+	return LangUnknown
+}
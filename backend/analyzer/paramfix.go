@@ -0,0 +1,432 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// followsUnusedIdiom reports whether name already marks a parameter of la
+// as intentionally unused.
+func followsUnusedIdiom(la LanguageAnalyzer, name string) bool {
+	fixer, ok := la.(ParameterFixer)
+	if !ok {
+		return false
+	}
+	idiom := fixer.UnusedParameterName(strings.TrimLeft(name, "_"))
+	return idiom != "" && idiom == name
+}
+
+// parameterRenameFix returns the fix renaming the unused parameter p, whose
+// position is complete, to la's idiom, or nil. Only an identifier found at
+// that position outside of comments and strings is renamed.
+func parameterRenameFix(la LanguageAnalyzer, file AnalyzeFile, p CodeIssue) *Fix {
+	fixer, ok := la.(ParameterFixer)
+	if !ok || !isIdentifier(p.Symbol) {
+		return nil
+	}
+	name := fixer.UnusedParameterName(p.Symbol)
+	if name == "" || name == p.Symbol {
+		return nil
+	}
+	src := newSourceText(file.Content)
+	sp := Span{Line: p.Line, Column: p.Column, EndLine: p.EndLine, EndColumn: p.EndColumn}
+	start, end := src.offsets(sp)
+	if file.Content[start:end] != p.Symbol || maskCode(file.Content, la.Comments())[start:end] != p.Symbol {
+		return nil
+	}
+	return &Fix{
+		Title: "Rename unused parameter " + p.Symbol + " to " + name,
+		Edits: []TextEdit{{File: file.Filename, Span: sp, NewText: name}},
+	}
+}
+
+// callSites finds the calls of workspace functions for parameter fixes.
+// Masked code is computed once per file. It is safe for concurrent use.
+type callSites struct {
+	files  []AnalyzeFile
+	parsed map[string]ParsedFile
+	cfg    *Config
+	root   string
+	mu     sync.Mutex
+	code   map[string]string
+}
+
+func newCallSites(files []AnalyzeFile, parsed map[string]ParsedFile, cfg *Config, root string) *callSites {
+	return &callSites{files: files, parsed: parsed, cfg: cfg, root: root, code: make(map[string]string)}
+}
+
+func (c *callSites) masked(file AnalyzeFile, la LanguageAnalyzer) string {
+	c.mu.Lock()
+	code, ok := c.code[file.Filename]
+	c.mu.Unlock()
+	if !ok {
+		code = maskCode(file.Content, la.Comments())
+		c.mu.Lock()
+		c.code[file.Filename] = code
+		c.mu.Unlock()
+	}
+	return code
+}
+
+// listItem is an element of a parenthesized, comma separated list with
+// surrounding whitespace trimmed.
+type listItem struct {
+	start, end int
+}
+
+// splitList splits the list whose opening parenthesis is at open in code.
+// It reports false when the list is not closed.
+func splitList(code string, open int) ([]listItem, bool) {
+	var items []listItem
+	depth := 0
+	start := open + 1
+	add := func(end int) {
+		s, e := start, end
+		for s < e && isSpaceByte(code[s]) {
+			s++
+		}
+		for e > s && isSpaceByte(code[e-1]) {
+			e--
+		}
+		if s < e {
+			items = append(items, listItem{start: s, end: e})
+		}
+	}
+	for i := open + 1; i < len(code); i++ {
+		switch code[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				add(i)
+				return items, code[i] == ')'
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				add(i)
+				start = i + 1
+			}
+		}
+	}
+	return nil, false
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// enclosingParen returns the offset of the parenthesis opening the list
+// that contains offset at, or -1.
+func enclosingParen(code string, at int) int {
+	depth := 0
+	for i := at - 1; i >= 0; i-- {
+		switch code[i] {
+		case ')', ']', '}':
+			depth++
+		case '[', '{':
+			if depth == 0 {
+				return -1
+			}
+			depth--
+		case '(':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ';':
+			if depth == 0 {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// identBefore returns the identifier ending at end, skipping spaces and
+// tabs before end.
+func identBefore(code string, end int) (string, int) {
+	end = skipSpaceBack(code, end)
+	start := end
+	for start > 0 && isIdentByte(code[start-1]) {
+		start--
+	}
+	return code[start:end], start
+}
+
+// plainArgument reports whether arg is passed by position: it is no
+// keyword argument and does not spread a collection.
+func plainArgument(arg string) bool {
+	if strings.HasPrefix(arg, "*") || strings.HasPrefix(arg, "&") || strings.Contains(arg, "...") {
+		return false
+	}
+	return !keywordArgument(arg)
+}
+
+// keywordArgument reports whether s starts like a keyword argument, e.g.
+// "a=1" in Python or "a: 1" in Ruby and PHP.
+func keywordArgument(s string) bool {
+	i := 0
+	for i < len(s) && isIdentByte(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return false
+	}
+	rest := strings.TrimLeft(s[i:], " \t")
+	if strings.HasPrefix(rest, "=") {
+		return !strings.HasPrefix(rest, "==") && !strings.HasPrefix(rest, "=>")
+	}
+	return strings.HasPrefix(rest, ":") && !strings.HasPrefix(rest, "::")
+}
+
+// paramDeclaration is the function declaring an unused parameter.
+type paramDeclaration struct {
+	name      string
+	nameStart int
+	params    []listItem
+	index     int
+}
+
+// declaration finds the function declaring the parameter at offset at in
+// code and checks that its parameter list can lose that parameter.
+func declaration(code string, at int, keyword string) (paramDeclaration, bool) {
+	open := enclosingParen(code, at)
+	if open < 0 {
+		return paramDeclaration{}, false
+	}
+	name, nameStart := identBefore(code, open)
+	kw, kwStart := identBefore(code, nameStart)
+	if name == "" || kw != keyword || kwStart == nameStart || (kwStart > 0 && code[kwStart-1] != '\n') {
+		return paramDeclaration{}, false
+	}
+
+	params, ok := splitList(code, open)
+	if !ok {
+		return paramDeclaration{}, false
+	}
+	index := -1
+	for i, p := range params {
+		if strings.ContainsAny(code[p.start:p.start+1], "*&/") || strings.Contains(code[p.start:p.end], "...") {
+			return paramDeclaration{}, false
+		}
+		if p.start <= at && at < p.end {
+			index = i
+		}
+	}
+	if index < 0 {
+		return paramDeclaration{}, false
+	}
+
+	// In Go, "a, b int" declares both a and b as int: b cannot go alone.
+	text := code[params[index].start:params[index].end]
+	if index > 0 && strings.ContainsAny(text, " \t") && !strings.ContainsAny(text, ":=") {
+		prev := code[params[index-1].start:params[index-1].end]
+		if w, _ := identBefore(prev, len(prev)); w == prev {
+			return paramDeclaration{}, false
+		}
+	}
+	return paramDeclaration{name: name, nameStart: nameStart, params: params, index: index}, true
+}
+
+// workspaceFix returns the fix for the unused parameter p of file, whose
+// rename fix is rename. The parameter is removed together with the
+// argument of every call when the function is a private, undecorated
+// declaration at the start of a line outside of an entry point and every
+// occurrence of its name is a plain call, see callEdits. Otherwise it is
+// the rename, unless a call might pass the argument by name, or nil.
+func (c *callSites) workspaceFix(la LanguageAnalyzer, file AnalyzeFile, p CodeIssue, rename *Fix) *Fix {
+	fixer, ok := la.(ParameterFixer)
+	if !ok || p.Column == 0 {
+		return rename
+	}
+
+	if keyword := fixer.FunctionKeyword(); keyword != "" && !c.cfg.rulesFor(c.root, file.Filename, la.Language()).entryPoint {
+		code := c.masked(file, la)
+		src := newSourceText(file.Content)
+		decl, ok := declaration(code, src.offset(p.Line, p.Column), keyword)
+		if ok && c.private(file, decl) && !decorated(file.Content, decl.nameStart) {
+			if calls, ok := c.callEdits(la, file, decl); ok && len(calls) > 0 {
+				param := decl.params[decl.index]
+				edits := []TextEdit{src.itemDeletion(file.Filename, param.start, param.end, la.Comments())}
+				return &Fix{Title: removalTitle(p.Symbol, len(calls)), Edits: append(edits, calls...)}
+			}
+		}
+	}
+	if rename != nil && c.passedByName(la, p.Symbol) {
+		return nil
+	}
+	return rename
+}
+
+func removalTitle(name string, calls int) string {
+	title := "Remove unused parameter " + name
+	if calls == 1 {
+		return title + " and the argument of 1 call"
+	}
+	return title + " and the arguments of " + strconv.Itoa(calls) + " calls"
+}
+
+// private reports whether file declares decl's function as a definition
+// that is not Exported.
+func (c *callSites) private(file AnalyzeFile, decl paramDeclaration) bool {
+	for _, def := range c.parsed[file.Filename].Definitions {
+		if def.Name == decl.name {
+			return !def.Exported
+		}
+	}
+	return false
+}
+
+// decorated reports whether the declaration on the line holding offset at
+// of content follows a decorator or an attribute, such as "@app.route(...)"
+// in Python or "#[Route(...)]" in PHP, possibly spanning several lines.
+func decorated(content string, at int) bool {
+	start := strings.LastIndexByte(content[:at], '\n') + 1
+	depth := 0
+	for start > 0 {
+		end := start - 1
+		start = strings.LastIndexByte(content[:end], '\n') + 1
+		line := content[start:end]
+		for i := len(line) - 1; i >= 0; i-- {
+			switch line[i] {
+			case ')', ']':
+				depth++
+			case '(', '[':
+				depth--
+			}
+		}
+		trimmed := strings.TrimSpace(line)
+		if depth > 0 || (depth == 0 && trimmed == "") {
+			continue
+		}
+		return strings.HasPrefix(trimmed, "@") || strings.HasPrefix(trimmed, "#[")
+	}
+	return false
+}
+
+// callEdits returns the deletions of the argument for decl's parameter in
+// every call of decl's function among the files of la's language. It
+// reports false when any occurrence of the function name is not such a call
+// or the name is declared more than once.
+func (c *callSites) callEdits(la LanguageAnalyzer, declFile AnalyzeFile, decl paramDeclaration) ([]TextEdit, bool) {
+	keyword := la.(ParameterFixer).FunctionKeyword()
+	declarations := 0
+	var edits []TextEdit
+	for _, f := range c.files {
+		fla := languageFor(f.Filename)
+		if fla == nil || fla.Language() != la.Language() {
+			continue
+		}
+		parsed := c.parsed[f.Filename]
+		for _, def := range parsed.Definitions {
+			if def.Name == decl.name || strings.HasSuffix(def.Name, "."+decl.name) {
+				declarations++
+			}
+		}
+		if declarations > 1 {
+			return nil, false
+		}
+
+		importLines := make(map[int]bool)
+		for _, imp := range parsed.Imports {
+			if containsWord(imp.Text, decl.name) && imp.Name != decl.name {
+				return nil, false
+			}
+			from, to := imp.Statement.Line, imp.Statement.EndLine
+			if from == 0 {
+				from, to = imp.Line, imp.Line
+			}
+			for l := from; l <= to; l++ {
+				importLines[l] = true
+			}
+		}
+
+		code := c.masked(f, fla)
+		src := newSourceText(f.Content)
+		for pos := 0; ; pos++ {
+			i := strings.Index(code[pos:], decl.name)
+			if i < 0 {
+				break
+			}
+			pos += i
+			end := pos + len(decl.name)
+			if !isWordBoundary(code, pos) || !isWordEnd(code, end) {
+				continue
+			}
+			if f.Filename == declFile.Filename && pos == decl.nameStart {
+				continue
+			}
+			if line, _ := src.position(pos); importLines[line] {
+				continue
+			}
+			before := skipSpaceBack(code, pos)
+			if before > 0 && code[before-1] == '$' {
+				continue
+			}
+			if before > 0 && strings.ContainsRune(".:>\\", rune(code[before-1])) {
+				return nil, false
+			}
+			if kw, _ := identBefore(code, pos); kw == keyword {
+				return nil, false
+			}
+
+			open := skipSpaceForward(code, end)
+			if open >= len(code) || code[open] != '(' {
+				return nil, false
+			}
+			args, ok := splitList(code, open)
+			if !ok || len(args) != len(decl.params) {
+				return nil, false
+			}
+			for _, arg := range args {
+				if !plainArgument(code[arg.start:arg.end]) {
+					return nil, false
+				}
+			}
+			arg := args[decl.index]
+			edits = append(edits, src.itemDeletion(f.Filename, arg.start, arg.end, fla.Comments()))
+		}
+	}
+	return edits, true
+}
+
+// passedByName reports whether name might be passed as a keyword argument,
+// "name=" or "name:" following an opening parenthesis or a comma, in any
+// file of la's language.
+func (c *callSites) passedByName(la LanguageAnalyzer, name string) bool {
+	for _, f := range c.files {
+		fla := languageFor(f.Filename)
+		if fla == nil || fla.Language() != la.Language() {
+			continue
+		}
+		code := c.masked(f, fla)
+		for pos := 0; ; pos++ {
+			i := strings.Index(code[pos:], name)
+			if i < 0 {
+				break
+			}
+			pos += i
+			end := pos + len(name)
+			if !isWordBoundary(code, pos) || !isWordEnd(code, end) {
+				continue
+			}
+			before := pos
+			for before > 0 && isSpaceByte(code[before-1]) {
+				before--
+			}
+			if before == 0 || (code[before-1] != '(' && code[before-1] != ',') {
+				continue
+			}
+			if keywordArgument(code[pos:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecorated(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"def f(a):\n    pass\n", false},
+		{"x = 1\ndef f(a):\n    pass\n", false},
+		{"@app.route('/')\ndef f(a):\n    pass\n", true},
+		{"@app.route(\n    '/',\n    methods=['GET'],\n)\ndef f(a):\n    pass\n", true},
+		{"@cache\n\ndef f(a):\n    pass\n", true},
+		{"class A:\n    @staticmethod\n    def f(a):\n        pass\n", true},
+		{"<?php\n#[Route('/')]\nfunction f($a) {}\n", true},
+		{"<?php\n#[Route(\n    '/',\n)]\nfunction f($a) {}\n", true},
+		{"<?php\n// @deprecated\nfunction f($a) {}\n", false},
+		{"x = g(\n    1)\ndef f(a):\n    pass\n", false},
+	}
+	for _, tt := range tests {
+		at := strings.Index(tt.content, "f(")
+		if got := decorated(tt.content, at); got != tt.want {
+			t.Errorf("decorated(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestWorkspaceParameterFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		file   string // declaring the unused parameter
+		symbol string
+		title  string
+		want   map[string]string // files after applying a removal
+	}{
+		{
+			name: "called helper",
+			files: map[string]string{
+				"lib.py": "def _helper(a, b):\n    return a\n",
+				"app.py": "from lib import _helper\n\n_helper(1, 2)\n_helper(3, 4)\n",
+			},
+			file:   "lib.py",
+			symbol: "b",
+			title:  "Remove unused parameter b and the arguments of 2 calls",
+			want: map[string]string{
+				"lib.py": "def _helper(a):\n    return a\n",
+				"app.py": "from lib import _helper\n\n_helper(1)\n_helper(3)\n",
+			},
+		},
+		{
+			name: "unexported Go function",
+			files: map[string]string{
+				"go.mod":  "module example.com/app\n\ngo 1.22\n",
+				"main.go": "package main\n\nfunc add(a, b int, c string) int {\n\treturn a + b\n}\n\nfunc main() {\n\tadd(1, 2, \"x\")\n}\n",
+			},
+			file:   "main.go",
+			symbol: "c",
+			title:  "Remove unused parameter c and the argument of 1 call",
+			want: map[string]string{
+				"main.go": "package main\n\nfunc add(a, b int) int {\n\treturn a + b\n}\n\nfunc main() {\n\tadd(1, 2)\n}\n",
+			},
+		},
+		{
+			name: "never called",
+			files: map[string]string{
+				"lib.py": "def _helper(a, b):\n    return a\n",
+			},
+			file:   "lib.py",
+			symbol: "b",
+			title:  "Rename unused parameter b to _b",
+		},
+		{
+			name: "decorated",
+			files: map[string]string{
+				"app.py": "@app.route('/')\ndef index(a, b):\n    return a\n\nindex(1, 2)\n",
+			},
+			file:   "app.py",
+			symbol: "b",
+			title:  "Rename unused parameter b to _b",
+		},
+		{
+			name: "exported Go function",
+			files: map[string]string{
+				"go.mod":     "module example.com/app\n\ngo 1.22\n",
+				"lib/lib.go": "package lib\n\nfunc Run(a int, b string) int {\n\treturn a\n}\n",
+				"main.go":    "package main\n\nimport \"example.com/app/lib\"\n\nfunc main() {\n\tlib.Run(1, \"x\")\n}\n",
+			},
+			file:   "lib/lib.go",
+			symbol: "b",
+			title:  "Rename unused parameter b to _",
+		},
+		{
+			name: "referenced as a value",
+			files: map[string]string{
+				"lib.py": "def _helper(a, b):\n    return a\n\n_helper(1, 2)\ncallbacks = [_helper]\n",
+			},
+			file:   "lib.py",
+			symbol: "b",
+			title:  "Rename unused parameter b to _b",
+		},
+	}
+	for _, tt := range tests {
+		req := WorkspaceAnalyzeRequest{}
+		for name, content := range tt.files {
+			req.Files = append(req.Files, AnalyzeFile{Filename: name, Content: content})
+		}
+		var fix *Fix
+		for _, issue := range New().AnalyzeWorkspace(req).Results[tt.file].Parameters {
+			if issue.Symbol == tt.symbol {
+				fix = issue.Fix
+			}
+		}
+		if fix == nil {
+			t.Errorf("%s: unused parameter %q has no fix", tt.name, tt.symbol)
+			continue
+		}
+		if fix.Title != tt.title {
+			t.Errorf("%s: fix %q, want %q", tt.name, fix.Title, tt.title)
+		}
+
+		edits := make(map[string][]TextEdit)
+		for _, edit := range fix.Edits {
+			edits[edit.File] = append(edits[edit.File], edit)
+		}
+		for name, want := range tt.want {
+			got, skipped := ApplyTextEdits(tt.files[name], edits[name])
+			if len(skipped) > 0 {
+				t.Errorf("%s: %s skipped edits %+v", tt.name, name, skipped)
+			}
+			if got != want {
+				t.Errorf("%s: %s after the fix is\n%q\nwant\n%q", tt.name, name, got, want)
+			}
+		}
+	}
+}
+
+func TestParameterRenameFixNeedsIdentifierInCode(t *testing.T) {
+	const content = "<script>\n  onMount(() => {});\n  // (a)\n  const f = (b) => \"b\";\n</script>\n"
+	la := languageFor("App.svelte")
+	file := AnalyzeFile{Filename: "App.svelte", Content: content}
+	tests := []struct {
+		symbol       string
+		line, column int
+		fixed        bool
+	}{
+		{"(", 2, 11, false},
+		{"a", 3, 7, false},
+		{"b", 4, 21, false},
+		{"b", 4, 14, true},
+	}
+	for _, tt := range tests {
+		p := CodeIssue{Symbol: tt.symbol, Line: tt.line, Column: tt.column, EndLine: tt.line, EndColumn: tt.column + len(tt.symbol)}
+		if fix := parameterRenameFix(la, file, p); (fix != nil) != tt.fixed {
+			t.Errorf("%q at %d:%d: fix %+v, want one = %v", tt.symbol, tt.line, tt.column, fix, tt.fixed)
+		}
+	}
+
+	for _, issue := range New().Analyze(AnalyzeRequest{Filename: file.Filename, Content: content}).Parameters {
+		if issue.Symbol != "b" {
+			t.Errorf("unused parameter %q reported", issue.Symbol)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DefaultParseCacheLimits bounds a snapshot when no limits are given.
+var DefaultParseCacheLimits = ParseCacheLimits{MaxEntries: 50000, MaxBytes: 256 << 20}
+
+// ParseCacheLimits bounds the size of a ParseCache snapshot. Entries are
+// kept in order of last use, so the files analyzed most recently survive
+// eviction. A zero field takes its value from DefaultParseCacheLimits and a
+// negative one means no limit.
+type ParseCacheLimits struct {
+	MaxEntries int `json:"maxEntries,omitempty"`
+	// MaxBytes bounds the approximate size of the parses, which is mostly
+	// the code they keep for finding references.
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+}
+
+// ParseCache is a serializable snapshot of an analyzer's parses, so that
+// the next process skips parsing the files that did not change. An entry
+// is only used for a file with the same filename and content hash parsed
+// by the same analyzer version, see cachedParse, so a stale cache costs
+// time but never changes results. Results are not saved; they depend on
+// the whole workspace and are cheap to rebuild.
+type ParseCache struct {
+	Version string            `json:"version"`
+	Entries []ParseCacheEntry `json:"entries"`
+}
+
+// ParseCacheEntry is the parse of one file, most recently used first in a
+// ParseCache.
+type ParseCacheEntry struct {
+	Filename string `json:"filename"`
+	ParsedWorkspaceEntry
+}
+
+// parseCacheItemSize approximates the size of a definition, import or
+// parameter besides its text.
+const parseCacheItemSize = 128
+
+func (l ParseCacheLimits) withDefaults() ParseCacheLimits {
+	if l.MaxEntries == 0 {
+		l.MaxEntries = DefaultParseCacheLimits.MaxEntries
+	}
+	if l.MaxBytes == 0 {
+		l.MaxBytes = DefaultParseCacheLimits.MaxBytes
+	}
+	return l
+}
+
+// size approximates the memory and encoded size of e.
+func (e ParseCacheEntry) size() int64 {
+	size := len(e.Filename) + len(e.Hash) + len(e.Code)
+	for _, def := range e.Definitions {
+		size += len(def.Name) + parseCacheItemSize
+	}
+	for _, imp := range e.Imports {
+		size += len(imp.Name) + len(imp.Source) + len(imp.Text) + parseCacheItemSize
+	}
+	for _, p := range e.Parameters {
+		size += len(p.Text) + parseCacheItemSize
+	}
+	return int64(size)
+}
+
+// ExportParseCache returns the current parses within limits, most recently
+// used first. Parses of files the workspace holds are kept before others.
+func (a *MultiLangAnalyzer) ExportParseCache(limits ParseCacheLimits) ParseCache {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limits = limits.withDefaults()
+	entries := make([]ParseCacheEntry, 0, len(a.parsedFiles))
+	for filename, entry := range a.parsedFiles {
+		if entry.Version == analyzerCacheVersion {
+			entries = append(entries, ParseCacheEntry{Filename: filename, ParsedWorkspaceEntry: entry})
+		}
+	}
+	inWorkspace := func(e ParseCacheEntry) bool {
+		if a.ws == nil {
+			return false
+		}
+		f, ok := a.ws.files[e.Filename]
+		return ok && f.Hash == e.Hash
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if wi, wj := inWorkspace(entries[i]), inWorkspace(entries[j]); wi != wj {
+			return wi
+		}
+		if entries[i].lastUsed != entries[j].lastUsed {
+			return entries[i].lastUsed > entries[j].lastUsed
+		}
+		return entries[i].Filename < entries[j].Filename
+	})
+
+	return ParseCache{Version: analyzerCacheVersion, Entries: evict(entries, limits)}
+}
+
+// evict returns the longest prefix of entries within limits.
+func evict(entries []ParseCacheEntry, limits ParseCacheLimits) []ParseCacheEntry {
+	if limits.MaxEntries >= 0 && len(entries) > limits.MaxEntries {
+		entries = entries[:limits.MaxEntries]
+	}
+	if limits.MaxBytes < 0 {
+		return entries
+	}
+	var total int64
+	for i, e := range entries {
+		total += e.size()
+		if total > limits.MaxBytes {
+			return entries[:i]
+		}
+	}
+	return entries
+}
+
+// ImportParseCache adds the parses of cache, as returned by
+// ExportParseCache, to the analyzer and returns how many it took. A cache
+// of another analyzer version is ignored, and parses the analyzer already
+// has are kept. Imported parses count as older than all current ones.
+func (a *MultiLangAnalyzer) ImportParseCache(cache ParseCache) int {
+	if cache.Version != analyzerCacheVersion {
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var taken []ParseCacheEntry
+	for _, e := range cache.Entries {
+		if _, ok := a.parsedFiles[e.Filename]; ok || e.Filename == "" || e.Version != analyzerCacheVersion {
+			continue
+		}
+		taken = append(taken, e)
+	}
+	forEach(len(taken), func(i int) {
+		taken[i].Words = identifiers(languageFor(taken[i].Filename), taken[i].Code)
+	})
+	oldest := int64(0)
+	for _, entry := range a.parsedFiles {
+		if entry.lastUsed < oldest {
+			oldest = entry.lastUsed
+		}
+	}
+	for i, e := range taken {
+		e.lastUsed = oldest - int64(i) - 1
+		a.parsedFiles[e.Filename] = e.ParsedWorkspaceEntry
+	}
+	return len(taken)
+}
+
+// DecodeParseCache decodes a ParseCache encoded as JSON.
+func DecodeParseCache(data []byte) (ParseCache, error) {
+	var cache ParseCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return ParseCache{}, fmt.Errorf("invalid parse cache: %w", err)
+	}
+	return cache, nil
+}
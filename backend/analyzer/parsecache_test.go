@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCacheRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"lib.py":  "import os\nimport sys\n\ndef used():\n    return sys.argv\n",
+		"app.py":  "from lib import used\n\nused()\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {}\n",
+	}
+	req := workspaceRequest(files, nil)
+	a := New()
+	want := a.AnalyzeWorkspace(req).Results
+
+	data, err := json.Marshal(a.ExportParseCache(ParseCacheLimits{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decode := func() ParseCache {
+		cache, err := DecodeParseCache(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache
+	}
+
+	warm := New()
+	if n := warm.ImportParseCache(decode()); n != 3 {
+		t.Errorf("imported %d parses, want one per analyzed file, 3", n)
+	}
+	got := warm.AnalyzeWorkspace(req).Results
+	for name := range want {
+		if g, w := resultJSON(t, got[name]), resultJSON(t, want[name]); g != w {
+			t.Errorf("%s from the imported cache: %s\nwant %s", name, g, w)
+		}
+	}
+
+	// A parse from the cache is used as is, without parsing the file again.
+	cache := decode()
+	for i := range cache.Entries {
+		if cache.Entries[i].Filename == "lib.py" {
+			cache.Entries[i].Imports = nil
+		}
+	}
+	reused := New()
+	reused.ImportParseCache(cache)
+	if issues := reused.AnalyzeWorkspace(req).Results["lib.py"].Imports; len(issues) != 0 {
+		t.Errorf("lib.py was parsed again: unused imports %+v, want the cached none", issues)
+	}
+
+	// Unless the file has changed since.
+	edited := workspaceRequest(map[string]string{"lib.py": files["lib.py"] + "\n"}, nil)
+	reused = New()
+	reused.ImportParseCache(cache)
+	if issues := reused.AnalyzeWorkspace(edited).Results["lib.py"].Imports; len(issues) != 1 || issues[0].Symbol != "os" {
+		t.Errorf("edited lib.py: unused imports %+v, want os", issues)
+	}
+}
+
+func TestImportParseCacheSkips(t *testing.T) {
+	a := New()
+	a.AnalyzeWorkspace(workspaceRequest(map[string]string{"a.py": "import os\n", "b.py": "import sys\n"}, nil))
+	cache := a.ExportParseCache(ParseCacheLimits{})
+
+	if n := New().ImportParseCache(ParseCache{Version: "old", Entries: cache.Entries}); n != 0 {
+		t.Errorf("cache of another version: imported %d parses, want 0", n)
+	}
+
+	stale := append([]ParseCacheEntry(nil), cache.Entries...)
+	stale[0].Version = "old"
+	stale = append(stale, ParseCacheEntry{ParsedWorkspaceEntry: stale[1].ParsedWorkspaceEntry})
+	if n := New().ImportParseCache(ParseCache{Version: cache.Version, Entries: stale}); n != 1 {
+		t.Errorf("entry of another version and one without a filename: imported %d parses, want 1", n)
+	}
+
+	// Parses the analyzer already has win over the cache.
+	b := New()
+	b.AnalyzeWorkspace(workspaceRequest(map[string]string{"a.py": "import json\n"}, nil))
+	if n := b.ImportParseCache(cache); n != 1 {
+		t.Errorf("analyzer with a.py: imported %d parses, want b.py only", n)
+	}
+	if got := b.ExportParseCache(ParseCacheLimits{}).Entries[0]; got.Filename != "a.py" || got.Imports[0].Name != "json" {
+		t.Errorf("first entry after importing is %s importing %s, want the current a.py importing json", got.Filename, got.Imports[0].Name)
+	}
+}
+
+func TestExportParseCacheLimits(t *testing.T) {
+	// Files are parsed in the order of their names, so c.py is the most
+	// recently used.
+	a := New()
+	a.AnalyzeWorkspace(workspaceRequest(map[string]string{"a.py": "import a\n", "b.py": "import b\n", "c.py": "import c\n"}, nil))
+	all := a.ExportParseCache(ParseCacheLimits{MaxEntries: -1, MaxBytes: -1}).Entries
+	sizes := make(map[string]int64)
+	for _, e := range all {
+		sizes[e.Filename] = e.size()
+	}
+
+	tests := []struct {
+		name   string
+		limits ParseCacheLimits
+		want   []string
+	}{
+		{"defaults", ParseCacheLimits{}, []string{"c.py", "b.py", "a.py"}},
+		{"no limits", ParseCacheLimits{MaxEntries: -1, MaxBytes: -1}, []string{"c.py", "b.py", "a.py"}},
+		{"entries", ParseCacheLimits{MaxEntries: 2}, []string{"c.py", "b.py"}},
+		{"bytes", ParseCacheLimits{MaxBytes: sizes["c.py"] + sizes["b.py"] - 1}, []string{"c.py"}},
+		{"bytes exactly", ParseCacheLimits{MaxBytes: sizes["c.py"] + sizes["b.py"]}, []string{"c.py", "b.py"}},
+		{"smaller than one entry", ParseCacheLimits{MaxBytes: 1}, []string{}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, e := range a.ExportParseCache(tt.limits).Entries {
+			got = append(got, e.Filename)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: kept %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Imported parses are older than the analyzer's own, and keep their
+	// order among themselves.
+	b := New()
+	b.AnalyzeWorkspace(workspaceRequest(map[string]string{"d.py": "import d\n", "e.py": "import e\n"}, nil))
+	b.ImportParseCache(ParseCache{Version: analyzerCacheVersion, Entries: all})
+	var got []string
+	for _, e := range b.ExportParseCache(ParseCacheLimits{}).Entries {
+		got = append(got, e.Filename)
+	}
+	if want := []string{"e.py", "d.py", "c.py", "b.py", "a.py"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after importing: order %v, want %v", got, want)
+	}
+}
+
+func TestDecodeParseCacheErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{"version": "v", "entries": []}`, ""},
+		{`{"version": "v", "entries": [{"filename": "a.py", "hash": "1"}]}`, ""},
+		{`[]`, "invalid parse cache"},
+		{`{"entries": {}}`, "invalid parse cache"},
+		{``, "invalid parse cache"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeParseCache([]byte(tt.data))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("DecodeParseCache(%s) = %v, want no error", tt.data, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("DecodeParseCache(%s) = %v, want an error containing %q", tt.data, err, tt.err)
+		}
+	}
+}
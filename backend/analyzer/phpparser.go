@@ -0,0 +1,692 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type PHPTokenType int
+
+const (
+	PHPTokenUse PHPTokenType = iota
+	PHPTokenFunction
+	PHPTokenClass
+	PHPTokenInterface
+	PHPTokenTrait
+	PHPTokenExtends
+	PHPTokenImplements
+	PHPTokenIdentifier
+	PHPTokenString
+	PHPTokenLParen
+	PHPTokenRParen
+	PHPTokenLBrace
+	PHPTokenRBrace
+	PHPTokenComma
+	PHPTokenSemi
+	PHPTokenNamespace
+	PHPTokenNewline
+	PHPTokenEOF
+	PHPTokenUnknown
+)
+
+// PHPToken is a token of PHP source. For variables Column points after the
+// $ sigil.
+type PHPToken struct {
+	Type   PHPTokenType
+	Value  string
+	Line   int
+	Column int
+}
+
+type PHPTokenizer struct {
+	content   string
+	pos       int
+	line      int
+	lineStart int
+	tokens    []PHPToken
+}
+
+func NewPHPTokenizer(content string) *PHPTokenizer {
+	return &PHPTokenizer{
+		content: content,
+		pos:     0,
+		line:    1,
+		tokens:  make([]PHPToken, 0),
+	}
+}
+
+func (t *PHPTokenizer) peek() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(t.content[t.pos:])
+	return r
+}
+
+func (t *PHPTokenizer) next() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(t.content[t.pos:])
+	t.pos += size
+	if r == '\n' {
+		t.line++
+		t.lineStart = t.pos
+	}
+	return r
+}
+
+// column returns the column of the byte offset pos on the current line.
+func (t *PHPTokenizer) column(pos int) int {
+	return utf16Column(t.content[t.lineStart:], pos-t.lineStart)
+}
+
+func (t *PHPTokenizer) Tokenize() []PHPToken {
+	inString := false
+	var stringChar rune
+	var stringStart int
+	var stringLine int
+	var stringColumn int
+
+	for t.pos < len(t.content) {
+		ch := t.peek()
+
+		if ch == '<' && t.pos+1 < len(t.content) && t.content[t.pos:t.pos+2] == "<?" {
+			t.next()
+			t.next()
+			continue
+		}
+
+		if inString {
+			if ch == stringChar && t.pos > 0 && t.content[t.pos-1] != '\\' {
+				t.next()
+				inString = false
+				t.tokens = append(t.tokens, PHPToken{Type: PHPTokenString, Value: t.content[stringStart:t.pos], Line: stringLine, Column: stringColumn})
+			} else {
+				t.next()
+			}
+			continue
+		}
+
+		if ch == 0 {
+			break
+		}
+
+		if ch == '\n' {
+			column := t.column(t.pos)
+			line := t.line
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenNewline, Value: "\n", Line: line, Column: column})
+			continue
+		}
+
+		if unicode.IsSpace(ch) {
+			t.next()
+			continue
+		}
+
+		if ch == '/' {
+			t.next()
+			nextCh := t.peek()
+			if nextCh == '/' {
+				for t.peek() != '\n' && t.peek() != 0 {
+					t.next()
+				}
+				continue
+			} else if nextCh == '*' {
+				t.next()
+				for {
+					if t.peek() == 0 {
+						break
+					}
+					if t.peek() == '*' {
+						t.next()
+						if t.peek() == '/' {
+							t.next()
+							break
+						}
+					} else {
+						t.next()
+					}
+				}
+				continue
+			}
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenUnknown, Value: "/", Line: t.line})
+			continue
+		}
+
+		if ch == '"' || ch == '\'' {
+			inString = true
+			stringChar = ch
+			stringStart = t.pos
+			stringLine = t.line
+			stringColumn = t.column(t.pos)
+			t.next()
+			continue
+		}
+
+		if unicode.IsLetter(ch) || ch == '_' || ch == '\\' {
+			t.tokens = append(t.tokens, t.readIdentifier())
+			continue
+		}
+
+		if unicode.IsDigit(ch) {
+			t.next()
+			for unicode.IsDigit(t.peek()) {
+				t.next()
+			}
+			continue
+		}
+
+		column := t.column(t.pos)
+		switch ch {
+		case '(':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenLParen, Value: "(", Line: t.line, Column: column})
+		case ')':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenRParen, Value: ")", Line: t.line, Column: column})
+		case '{':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenLBrace, Value: "{", Line: t.line, Column: column})
+		case '}':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenRBrace, Value: "}", Line: t.line, Column: column})
+		case ',':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenComma, Value: ",", Line: t.line, Column: column})
+		case ';':
+			t.next()
+			t.tokens = append(t.tokens, PHPToken{Type: PHPTokenSemi, Value: ";", Line: t.line, Column: column})
+		default:
+			t.next()
+		}
+	}
+
+	t.tokens = append(t.tokens, PHPToken{Type: PHPTokenEOF, Value: "", Line: t.line})
+	return t.tokens
+}
+
+func (t *PHPTokenizer) readIdentifier() PHPToken {
+	start := t.pos
+	line := t.line
+	column := t.column(start)
+	for unicode.IsLetter(t.peek()) || unicode.IsDigit(t.peek()) || t.peek() == '_' || t.peek() == '\\' {
+		t.next()
+	}
+	value := t.content[start:t.pos]
+
+	switch value {
+	case "use":
+		return PHPToken{Type: PHPTokenUse, Value: value, Line: line, Column: column}
+	case "function":
+		return PHPToken{Type: PHPTokenFunction, Value: value, Line: line, Column: column}
+	case "class":
+		return PHPToken{Type: PHPTokenClass, Value: value, Line: line, Column: column}
+	case "interface":
+		return PHPToken{Type: PHPTokenInterface, Value: value, Line: line, Column: column}
+	case "trait":
+		return PHPToken{Type: PHPTokenTrait, Value: value, Line: line, Column: column}
+	case "extends":
+		return PHPToken{Type: PHPTokenExtends, Value: value, Line: line, Column: column}
+	case "implements":
+		return PHPToken{Type: PHPTokenImplements, Value: value, Line: line, Column: column}
+	case "namespace":
+		return PHPToken{Type: PHPTokenNamespace, Value: value, Line: line, Column: column}
+	default:
+		return PHPToken{Type: PHPTokenIdentifier, Value: value, Line: line, Column: column}
+	}
+}
+
+// PHPImportItem is a name imported by a use statement; start and end are
+// the first token of the name and the last of its alias, while stmtStart
+// and stmtEnd are the use keyword and the closing semicolon. name is the
+// alias or else the last segment of the path.
+type PHPImportItem struct {
+	name      string
+	fullPath  string
+	line      int
+	text      string
+	start     PHPToken
+	end       PHPToken
+	stmtStart PHPToken
+	stmtEnd   PHPToken
+}
+
+func FindPHPImports(content string) []PHPImportItem {
+	t := NewPHPTokenizer(content)
+	tokens := t.Tokenize()
+
+	imports := findPHPImportsFromTokens(tokens)
+	return imports
+}
+
+// findPHPImportsFromTokens parses use statements importing one or more
+// comma separated names, optionally grouped under a common prefix as in
+// "use App\Models\{User, Post as Article};". Closure use clauses are
+// skipped as they are not followed by a name.
+func findPHPImportsFromTokens(tokens []PHPToken) []PHPImportItem {
+	var imports []PHPImportItem
+
+	skipNewlines := func(i int) int {
+		for i < len(tokens) && tokens[i].Type == PHPTokenNewline {
+			i++
+		}
+		return i
+	}
+	useType := func(i int) (string, int) {
+		if i < len(tokens) && (tokens[i].Type == PHPTokenFunction || tokens[i].Value == "const") {
+			return tokens[i].Value, skipNewlines(i + 1)
+		}
+		return "", i
+	}
+
+	var i int
+	for i < len(tokens) {
+		if tokens[i].Type != PHPTokenUse {
+			i++
+			continue
+		}
+
+		stmtStart := tokens[i]
+		kind, j := useType(skipNewlines(i + 1))
+		if j >= len(tokens) || tokens[j].Type != PHPTokenIdentifier {
+			i++
+			continue
+		}
+
+		var items []PHPImportItem
+		add := func(prefix, kind string, j int) int {
+			item := PHPImportItem{
+				fullPath: strings.TrimPrefix(prefix+tokens[j].Value, "\\"),
+				line:     tokens[j].Line,
+				start:    tokens[j],
+				end:      tokens[j],
+			}
+			item.name = item.fullPath[strings.LastIndex(item.fullPath, "\\")+1:]
+			item.text = "use "
+			if kind != "" {
+				item.text += kind + " "
+			}
+			item.text += item.fullPath
+			j = skipNewlines(j + 1)
+			if j+1 < len(tokens) && tokens[j].Value == "as" && tokens[j+1].Type == PHPTokenIdentifier {
+				item.name = tokens[j+1].Value
+				item.text += " as " + item.name
+				item.end = tokens[j+1]
+				j = skipNewlines(j + 2)
+			}
+			item.text += ";"
+			items = append(items, item)
+			return j
+		}
+
+		for j < len(tokens) && tokens[j].Type == PHPTokenIdentifier {
+			if j+1 < len(tokens) && tokens[j+1].Type == PHPTokenLBrace {
+				prefix := tokens[j].Value
+				j = skipNewlines(j + 2)
+				for j < len(tokens) {
+					groupKind, k := useType(j)
+					if k >= len(tokens) || tokens[k].Type != PHPTokenIdentifier {
+						break
+					}
+					if groupKind == "" {
+						groupKind = kind
+					}
+					j = add(prefix, groupKind, k)
+					if j >= len(tokens) || tokens[j].Type != PHPTokenComma {
+						break
+					}
+					j = skipNewlines(j + 1)
+				}
+				if j < len(tokens) && tokens[j].Type == PHPTokenRBrace {
+					j = skipNewlines(j + 1)
+				}
+			} else {
+				j = add("", kind, j)
+			}
+			if j >= len(tokens) || tokens[j].Type != PHPTokenComma {
+				break
+			}
+			j = skipNewlines(j + 1)
+		}
+
+		if j >= len(tokens) || tokens[j].Type != PHPTokenSemi {
+			i = j
+			continue
+		}
+		for _, item := range items {
+			item.stmtStart = stmtStart
+			item.stmtEnd = tokens[j]
+			imports = append(imports, item)
+		}
+		i = j + 1
+	}
+
+	return imports
+}
+
+// PHPDefinition is a declared function, class-like type or constant.
+// modifiers are the keywords before a member, such as "private" or
+// "static". exported is set for what other files can refer to: top-level
+// declarations and members that are neither private nor protected. member
+// is set for declarations in a class, interface or trait body.
+type PHPDefinition struct {
+	name      string
+	defType   string
+	line      int
+	column    int
+	modifiers []string
+	exported  bool
+	member    bool
+}
+
+var phpModifiers = map[string]bool{
+	"public": true, "protected": true, "private": true,
+	"static": true, "abstract": true, "final": true, "readonly": true,
+}
+
+func FindPHPDefinitions(content string) []PHPDefinition {
+	t := NewPHPTokenizer(content)
+	tokens := t.Tokenize()
+
+	var defs []PHPDefinition
+	// blocks holds the kind of every open brace: "namespace", "class" or
+	// "" for any other block. pending is the kind of the next brace.
+	var blocks []string
+	pending := ""
+	scope := func() string {
+		kind := "namespace"
+		for _, b := range blocks {
+			if b != "namespace" {
+				kind = b
+				if b == "" {
+					break
+				}
+			}
+		}
+		return kind
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case PHPTokenNamespace:
+			pending = "namespace"
+		case PHPTokenLBrace:
+			blocks = append(blocks, pending)
+			pending = ""
+		case PHPTokenRBrace:
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		case PHPTokenSemi:
+			pending = ""
+		}
+
+		// "use function" and "use const" import instead of declaring.
+		if i > 0 && tokens[i-1].Type == PHPTokenUse {
+			continue
+		}
+		defType := ""
+		switch {
+		case tokens[i].Type == PHPTokenFunction:
+			defType = "function"
+		case tokens[i].Type == PHPTokenClass:
+			defType = "class"
+		case tokens[i].Type == PHPTokenInterface:
+			defType = "interface"
+		case tokens[i].Type == PHPTokenTrait:
+			defType = "trait"
+		case tokens[i].Value == "const":
+			defType = "const"
+		}
+		if defType == "" || i+1 >= len(tokens) || tokens[i+1].Type != PHPTokenIdentifier {
+			continue
+		}
+
+		var modifiers []string
+		for j := i - 1; j >= 0 && tokens[j].Type == PHPTokenIdentifier && phpModifiers[strings.ToLower(tokens[j].Value)]; j-- {
+			modifiers = append([]string{strings.ToLower(tokens[j].Value)}, modifiers...)
+		}
+		exported := false
+		member := scope() == "class"
+		switch scope() {
+		case "namespace":
+			exported = true
+		case "class":
+			exported = true
+			for _, m := range modifiers {
+				if m == "private" || m == "protected" {
+					exported = false
+				}
+			}
+		}
+		defs = append(defs, PHPDefinition{
+			name:      tokens[i+1].Value,
+			defType:   defType,
+			line:      tokens[i+1].Line,
+			column:    tokens[i+1].Column,
+			modifiers: modifiers,
+			exported:  exported,
+			member:    member,
+		})
+		if defType != "function" && defType != "const" {
+			pending = "class"
+		}
+	}
+
+	return defs
+}
+
+func FindPHPParameters(content, filename string) []CodeIssue {
+	t := NewPHPTokenizer(content)
+	tokens := t.Tokenize()
+
+	var params []CodeIssue
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == PHPTokenFunction && i+1 < len(tokens) {
+			parenCount := 0
+			paramStart := -1
+			for j := i + 2; j < len(tokens); j++ {
+				if tokens[j].Type == PHPTokenLParen {
+					if parenCount == 0 {
+						paramStart = j + 1
+					}
+					parenCount++
+				}
+				if tokens[j].Type == PHPTokenRParen {
+					parenCount--
+					if parenCount == 0 {
+						break
+					}
+				}
+			}
+
+			if paramStart > 0 && paramStart < len(tokens) {
+				for j := paramStart; j < len(tokens) && tokens[j].Type != PHPTokenRParen; j++ {
+					if tokens[j].Type == PHPTokenIdentifier && j+1 < len(tokens) && tokens[j+1].Type != PHPTokenIdentifier {
+						paramName := tokens[j].Value
+						if paramName != "" && paramName != "string" && paramName != "int" && paramName != "bool" &&
+							paramName != "float" && paramName != "array" && paramName != "void" && paramName != "mixed" &&
+							paramName != "null" && paramName != "true" && paramName != "false" {
+							params = append(params, CodeIssue{
+								Line:      tokens[j].Line,
+								Column:    tokens[j].Column,
+								EndLine:   tokens[j].Line,
+								EndColumn: tokens[j].Column + utf16Len(paramName),
+								Text:      "parameter " + paramName,
+								Kind:      "parameter",
+								Symbol:    paramName,
+								File:      filename,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return params
+}
+
+func FindUsedPHPNames(content string) map[string]int {
+	t := NewPHPTokenizer(content)
+	tokens := t.Tokenize()
+
+	counts := make(map[string]int)
+
+	for _, tok := range tokens {
+		if tok.Type == PHPTokenIdentifier {
+			counts[tok.Value]++
+		}
+	}
+
+	return counts
+}
+
+func init() {
+	RegisterLanguage(phpAnalyzer{})
+}
+
+type phpAnalyzer struct{}
+
+func (phpAnalyzer) Language() Language { return LangPHP }
+
+func (phpAnalyzer) Extensions() []string { return []string{".php"} }
+
+func (phpAnalyzer) Comments() CommentSyntax {
+	return CommentSyntax{Line: []string{"//", "#"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+func (phpAnalyzer) Identifiers(code string) map[string]bool {
+	return nameSet(FindUsedPHPNames(code))
+}
+
+func (phpAnalyzer) StandaloneDefinitions() bool { return false }
+
+// PHP has no idiom for unused parameters, so they are only ever removed.
+func (phpAnalyzer) UnusedParameterName(string) string { return "" }
+
+func (phpAnalyzer) FunctionKeyword() string { return "function" }
+
+func (phpAnalyzer) Parse(content, filename string) ParsedFile {
+	var parsed ParsedFile
+
+	for _, imp := range FindPHPImports(content) {
+		parsed.Imports = append(parsed.Imports, Import{
+			Name:      imp.name,
+			File:      filename,
+			Line:      imp.line,
+			Column:    imp.start.Column,
+			EndLine:   imp.end.Line,
+			EndColumn: imp.end.Column + utf16Len(imp.end.Value),
+			Source:    imp.fullPath,
+			Text:      imp.text,
+			Statement: Span{
+				Line:      imp.stmtStart.Line,
+				Column:    imp.stmtStart.Column,
+				EndLine:   imp.stmtEnd.Line,
+				EndColumn: imp.stmtEnd.Column + 1,
+			},
+		})
+	}
+
+	for _, d := range FindPHPDefinitions(content) {
+		parsed.Definitions = append(parsed.Definitions, Definition{
+			Name:     d.name,
+			Type:     d.defType,
+			Line:     d.line,
+			Column:   d.column,
+			File:     filename,
+			Exported: d.exported,
+			Member:   d.member,
+		})
+	}
+
+	parsed.Parameters = FindPHPParameters(content, filename)
+	parsed.Code = blankStatements(content, parsed.Imports)
+	return parsed
+}
+
+// IsTestFile reports whether filename is a PHPUnit test case, *Test.php.
+func (phpAnalyzer) IsTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "Test.php")
+}
+
+// ResolveImports resolves use statements by their namespace path, dropping
+// leading namespaces until a file matches, since autoloaders map the root
+// namespaces to arbitrary directories, and require and include statements
+// by their literal path. Classes of the same directory, which usually is
+// the same namespace, are used without a use statement, so the files there
+// whose base name occurs in the code count as well.
+func (phpAnalyzer) ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string {
+	dir := path.Dir(indexPath(file.Filename))
+	var deps []string
+	for _, imp := range parsed.Imports {
+		parts := strings.Split(strings.Trim(imp.Source, "\\"), "\\")
+		for i := range parts {
+			if found := ix.Find(strings.Join(parts[i:], "/") + ".php"); len(found) > 0 {
+				deps = append(deps, found...)
+				break
+			}
+		}
+	}
+
+	tokens := NewPHPTokenizer(parsed.Code).Tokenize()
+	for i, tok := range tokens {
+		switch strings.ToLower(tok.Value) {
+		case "require", "require_once", "include", "include_once":
+		default:
+			continue
+		}
+		fromDir := false
+		for j := i + 1; j < len(tokens) && j <= i+4 && tokens[j].Line == tok.Line; j++ {
+			if tokens[j].Value == "__DIR__" || tokens[j].Value == "dirname" {
+				fromDir = true
+			}
+			if tokens[j].Type != PHPTokenString {
+				continue
+			}
+			p := strings.Trim(tokens[j].Value, `"'`)
+			if filename, ok := ix.File(path.Join(dir, p)); ok && (fromDir || !path.IsAbs(p)) {
+				deps = append(deps, filename)
+			} else if !fromDir {
+				deps = append(deps, ix.Find(p)...)
+			}
+			break
+		}
+	}
+
+	words := codeWords(parsed.Code)
+	for _, other := range ix.Dir(dir) {
+		base := path.Base(slashPath(other))
+		if other != file.Filename && strings.HasSuffix(base, ".php") && words[strings.TrimSuffix(base, ".php")] {
+			deps = append(deps, other)
+		}
+	}
+	return deps
+}
+
+// UsedLocally counts a declared name as used from its second occurrence.
+// Imports are declared by their full path, so for them a single reference
+// outside of use statements is enough.
+func (phpAnalyzer) UsedLocally(content string, parsed ParsedFile) map[string]bool {
+	counts := FindUsedPHPNames(content)
+	used := make(map[string]bool)
+	for name, n := range counts {
+		if n > 1 {
+			used[name] = true
+		}
+	}
+	if len(parsed.Imports) > 0 {
+		refs := FindUsedPHPNames(blankStatements(content, parsed.Imports))
+		for _, imp := range parsed.Imports {
+			if refs[imp.Name] > 0 {
+				used[imp.Name] = true
+			}
+		}
+	}
+	return used
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import "sync"
+
+// Phases of AnalyzeWorkspaceContext, in the order they run.
+const (
+	// PhaseParse parses the files that changed since the last call.
+	PhaseParse = "parse"
+	// PhaseResolve runs the WorkspaceResolver of every language whose
+	// files changed, counting the files of those languages.
+	PhaseResolve = "resolve"
+	// PhaseBuild builds the results of the files a change may affect.
+	PhaseBuild = "build"
+)
+
+// Progress tells how many of the files of Phase are done.
+type Progress struct {
+	Phase string `json:"phase"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
+
+// ProgressFunc receives progress reports. Calls do not overlap, but they
+// may come from different goroutines.
+type ProgressFunc func(Progress)
+
+// progressCounter counts the files done in one phase for a ProgressFunc,
+// which may be nil.
+type progressCounter struct {
+	mu       sync.Mutex
+	fn       ProgressFunc
+	progress Progress
+}
+
+// newProgressCounter reports the start of phase.
+func newProgressCounter(fn ProgressFunc, phase string, total int) *progressCounter {
+	p := &progressCounter{fn: fn, progress: Progress{Phase: phase, Total: total}}
+	if fn != nil {
+		fn(p.progress)
+	}
+	return p
+}
+
+// add counts n more files as done.
+func (p *progressCounter) add(n int) {
+	if p.fn == nil || n == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Done += n
+	p.fn(p.progress)
+}
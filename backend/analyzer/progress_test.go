@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var progressFiles = map[string]string{
+	"go.mod":     "module example.com/app\n\ngo 1.22\n",
+	"main.go":    "package main\n\nimport \"example.com/app/lib\"\n\nfunc main() {\n\tlib.Run()\n}\n",
+	"lib/lib.go": "package lib\n\nfunc Run() {}\n\nfunc unused() {}\n",
+	"lib.py":     "import os\n\ndef used():\n    pass\n",
+	"app.py":     "from lib import used\n\nused()\n",
+}
+
+// phases checks that reports come phase by phase, each starting at zero
+// and counting up to at most its total, and returns "phase done/total" for
+// the last report of every phase.
+func phases(t *testing.T, reports []Progress) []string {
+	t.Helper()
+	var out []string
+	for i, p := range reports {
+		first := i == 0 || reports[i-1].Phase != p.Phase
+		switch {
+		case first && p.Done != 0:
+			t.Errorf("%s starts at %d", p.Phase, p.Done)
+		case !first && p.Done <= reports[i-1].Done:
+			t.Errorf("%s goes from %d to %d", p.Phase, reports[i-1].Done, p.Done)
+		case p.Done > p.Total:
+			t.Errorf("%s at %d of %d", p.Phase, p.Done, p.Total)
+		}
+		if first {
+			out = append(out, "")
+		}
+		out[len(out)-1] = fmt.Sprintf("%s %d/%d", p.Phase, p.Done, p.Total)
+	}
+	return out
+}
+
+func TestAnalyzeWorkspaceProgress(t *testing.T) {
+	files := make(map[string]string, len(progressFiles))
+	for name, content := range progressFiles {
+		files[name] = content
+	}
+	a := New()
+	steps := []struct {
+		name   string
+		change map[string]string
+		want   []string
+	}{
+		{
+			name: "cold",
+			want: []string{"parse 5/5", "resolve 2/2", "build 5/5"},
+		},
+		{
+			name: "unchanged",
+			want: []string{"parse 0/0", "resolve 0/0", "build 0/0"},
+		},
+		{
+			name:   "a Python file changed",
+			change: map[string]string{"lib.py": "import os\n\ndef used():\n    return os.sep\n"},
+			want:   []string{"parse 1/1", "resolve 0/0", "build 1/1"},
+		},
+		{
+			name:   "a Go file changed",
+			change: map[string]string{"lib/lib.go": "package lib\n\nfunc Run() {}\n"},
+			want:   []string{"parse 1/1", "resolve 2/2", "build 1/1"},
+		},
+	}
+	for _, step := range steps {
+		for name, content := range step.change {
+			files[name] = content
+		}
+		var reports []Progress
+		_, err := a.AnalyzeWorkspaceContext(context.Background(), workspaceRequest(files, nil), func(p Progress) {
+			reports = append(reports, p)
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := phases(t, reports); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: progress %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestAnalyzeWorkspaceCancel(t *testing.T) {
+	req := workspaceRequest(progressFiles, nil)
+	want := New().AnalyzeWorkspace(req).Results
+	edited := make(map[string]string, len(progressFiles))
+	for name, content := range progressFiles {
+		edited[name] = content
+	}
+	edited["app.py"] = "from lib import used\n"
+	wantEdited := New().AnalyzeWorkspace(workspaceRequest(edited, nil)).Results
+
+	for _, phase := range []string{PhaseParse, PhaseResolve, PhaseBuild} {
+		a := New()
+		a.AnalyzeWorkspace(req)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		res, err := a.AnalyzeWorkspaceContext(ctx, workspaceRequest(edited, nil), func(p Progress) {
+			if p.Phase == phase {
+				cancel()
+			}
+		})
+		cancel()
+		if !errors.Is(err, context.Canceled) || res.Results != nil {
+			t.Errorf("canceled in %s: %d results, error %v, want none and %v", phase, len(res.Results), err, context.Canceled)
+		}
+
+		// Whatever was kept, the next scan is complete and correct, and so
+		// are incremental updates after it.
+		got := a.AnalyzeWorkspace(workspaceRequest(edited, nil)).Results
+		if len(got) != len(wantEdited) {
+			t.Errorf("canceled in %s: next scan has %d results, want %d", phase, len(got), len(wantEdited))
+		}
+		for name := range wantEdited {
+			if g, w := resultJSON(t, got[name]), resultJSON(t, wantEdited[name]); g != w {
+				t.Errorf("canceled in %s: next scan of %s: %s\nwant %s", phase, name, g, w)
+			}
+		}
+		for name, res := range a.UpdateFiles([]AnalyzeFile{{Filename: "app.py", Content: progressFiles["app.py"]}}, nil).Results {
+			got[name] = res
+		}
+		for name := range want {
+			if g, w := resultJSON(t, got[name]), resultJSON(t, want[name]); g != w {
+				t.Errorf("canceled in %s: %s after an update: %s\nwant %s", phase, name, g, w)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a := New()
+	if _, err := a.AnalyzeWorkspaceContext(ctx, req, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled before the scan: error %v, want %v", err, context.Canceled)
+	}
+	if n := len(a.ExportParseCache(ParseCacheLimits{}).Entries); n != 0 {
+		t.Errorf("canceled before the scan: %d files parsed, want none", n)
+	}
+}
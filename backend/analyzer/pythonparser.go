@@ -0,0 +1,685 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type PyTokenType int
+
+const (
+	PyTokenImport PyTokenType = iota
+	PyTokenFrom
+	PyTokenDef
+	PyTokenClass
+	PyTokenIdentifier
+	PyTokenString
+	PyTokenLParen
+	PyTokenRParen
+	PyTokenComma
+	PyTokenDot
+	PyTokenNewline
+	PyTokenColon
+	PyTokenEquals
+	PyTokenAs
+	PyTokenEOF
+	PyTokenUnknown
+)
+
+// PyToken is a token of Python source.
+type PyToken struct {
+	Type   PyTokenType
+	Value  string
+	Line   int
+	Column int
+}
+
+type PyTokenizer struct {
+	content   string
+	pos       int
+	line      int
+	lineStart int
+	tokens    []PyToken
+}
+
+func NewPyTokenizer(content string) *PyTokenizer {
+	return &PyTokenizer{
+		content: content,
+		pos:     0,
+		line:    1,
+		tokens:  make([]PyToken, 0),
+	}
+}
+
+func (t *PyTokenizer) peek() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(t.content[t.pos:])
+	return r
+}
+
+func (t *PyTokenizer) next() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(t.content[t.pos:])
+	t.pos += size
+	if r == '\n' {
+		t.line++
+		t.lineStart = t.pos
+	}
+	return r
+}
+
+// column returns the column of the byte offset pos on the current line.
+func (t *PyTokenizer) column(pos int) int {
+	return utf16Column(t.content[t.lineStart:], pos-t.lineStart)
+}
+
+func (t *PyTokenizer) Tokenize() []PyToken {
+	for t.pos < len(t.content) {
+		ch := t.peek()
+
+		if ch == 0 {
+			break
+		}
+
+		if ch == '\n' {
+			column := t.column(t.pos)
+			line := t.line
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenNewline, Value: "\n", Line: line, Column: column})
+			continue
+		}
+
+		if unicode.IsSpace(ch) {
+			t.next()
+			continue
+		}
+
+		if ch == '#' {
+			for t.peek() != '\n' && t.peek() != 0 {
+				t.next()
+			}
+			continue
+		}
+
+		if ch == '"' || ch == '\'' {
+			t.tokens = append(t.tokens, t.readString(ch))
+			continue
+		}
+
+		if unicode.IsLetter(ch) || ch == '_' {
+			t.tokens = append(t.tokens, t.readIdentifier())
+			continue
+		}
+
+		if unicode.IsDigit(ch) {
+			t.next()
+			for unicode.IsDigit(t.peek()) {
+				t.next()
+			}
+			continue
+		}
+
+		column := t.column(t.pos)
+		switch ch {
+		case '(':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenLParen, Value: "(", Line: t.line, Column: column})
+		case ')':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenRParen, Value: ")", Line: t.line, Column: column})
+		case ',':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenComma, Value: ",", Line: t.line, Column: column})
+		case '.':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenDot, Value: ".", Line: t.line, Column: column})
+		case ':':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenColon, Value: ":", Line: t.line, Column: column})
+		case '=':
+			t.next()
+			t.tokens = append(t.tokens, PyToken{Type: PyTokenEquals, Value: "=", Line: t.line, Column: column})
+		default:
+			t.next()
+		}
+	}
+
+	t.tokens = append(t.tokens, PyToken{Type: PyTokenEOF, Value: "", Line: t.line})
+	return t.tokens
+}
+
+func (t *PyTokenizer) readString(quote rune) PyToken {
+	start := t.pos
+	line := t.line
+	column := t.column(start)
+	t.next()
+	for t.peek() != quote && t.peek() != 0 {
+		if t.peek() == '\\' {
+			t.next()
+			if t.peek() != 0 {
+				t.next()
+			}
+		} else {
+			t.next()
+		}
+	}
+	if t.peek() == quote {
+		t.next()
+	}
+	return PyToken{Type: PyTokenString, Value: t.content[start:t.pos], Line: line, Column: column}
+}
+
+func (t *PyTokenizer) readIdentifier() PyToken {
+	start := t.pos
+	line := t.line
+	column := t.column(start)
+	for unicode.IsLetter(t.peek()) || unicode.IsDigit(t.peek()) || t.peek() == '_' {
+		t.next()
+	}
+	value := t.content[start:t.pos]
+
+	switch value {
+	case "import":
+		return PyToken{Type: PyTokenImport, Value: value, Line: line, Column: column}
+	case "from":
+		return PyToken{Type: PyTokenFrom, Value: value, Line: line, Column: column}
+	case "def":
+		return PyToken{Type: PyTokenDef, Value: value, Line: line, Column: column}
+	case "class":
+		return PyToken{Type: PyTokenClass, Value: value, Line: line, Column: column}
+	case "as":
+		return PyToken{Type: PyTokenAs, Value: value, Line: line, Column: column}
+	default:
+		return PyToken{Type: PyTokenIdentifier, Value: value, Line: line, Column: column}
+	}
+}
+
+// PyImportItem is an import statement; start and end are its first and
+// last token. module is the module of a from import and empty for a plain
+// import, whose names are the modules themselves.
+type PyImportItem struct {
+	module string
+	names  []PyImportName
+	start  PyToken
+	end    PyToken
+}
+
+// PyImportName is one name of an import statement, from the first token of
+// the name to the last token of its alias.
+type PyImportName struct {
+	name  string
+	alias string
+	start PyToken
+	end   PyToken
+}
+
+// bound returns the name the import binds in the importing module: the
+// alias, or for a plain import of a dotted module its top-level package.
+func (n PyImportName) bound(fromImport bool) string {
+	if n.alias != "" {
+		return n.alias
+	}
+	if !fromImport {
+		if i := strings.Index(n.name, "."); i >= 0 {
+			return n.name[:i]
+		}
+	}
+	return n.name
+}
+
+func (n PyImportName) text(module string) string {
+	text := "import " + n.name
+	if module != "" {
+		text = "from " + module + " " + text
+	}
+	if n.alias != "" {
+		text += " as " + n.alias
+	}
+	return text
+}
+
+func FindPythonImports(content string) []PyImportItem {
+	t := NewPyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var imports []PyImportItem
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case PyTokenImport:
+			names, end, next := parsePyImportNames(tokens, i+1, true)
+			if len(names) > 0 {
+				imports = append(imports, PyImportItem{names: names, start: tokens[i], end: end})
+			}
+			i = next - 1
+
+		case PyTokenFrom:
+			// Relative modules start with dots: from . import x, from ..a import y
+			j := i + 1
+			var module strings.Builder
+			for j < len(tokens) && (tokens[j].Type == PyTokenDot || tokens[j].Type == PyTokenIdentifier) {
+				module.WriteString(tokens[j].Value)
+				j++
+			}
+			if module.Len() == 0 || j >= len(tokens) || tokens[j].Type != PyTokenImport {
+				continue
+			}
+			names, end, next := parsePyImportNames(tokens, j+1, false)
+			if len(names) > 0 {
+				imports = append(imports, PyImportItem{module: module.String(), names: names, start: tokens[i], end: end})
+			}
+			i = next - 1
+		}
+	}
+
+	return imports
+}
+
+// parsePyImportNames reads the comma separated names of an import statement
+// starting at tokens[i] and returns them with the last token of the
+// statement and the index after it. Plain imports name dotted modules,
+// while the names of a from import may be wrapped in parentheses spanning
+// several lines.
+func parsePyImportNames(tokens []PyToken, i int, dotted bool) ([]PyImportName, PyToken, int) {
+	var names []PyImportName
+	var end PyToken
+
+	paren := !dotted && i < len(tokens) && tokens[i].Type == PyTokenLParen
+	if paren {
+		i++
+	}
+	skipNewlines := func() {
+		for paren && i < len(tokens) && tokens[i].Type == PyTokenNewline {
+			i++
+		}
+	}
+
+	for {
+		skipNewlines()
+		if i >= len(tokens) || tokens[i].Type != PyTokenIdentifier {
+			break
+		}
+		n := PyImportName{name: tokens[i].Value, start: tokens[i], end: tokens[i]}
+		i++
+		for dotted && i+1 < len(tokens) && tokens[i].Type == PyTokenDot && tokens[i+1].Type == PyTokenIdentifier {
+			n.name += "." + tokens[i+1].Value
+			n.end = tokens[i+1]
+			i += 2
+		}
+		if i+1 < len(tokens) && tokens[i].Type == PyTokenAs && tokens[i+1].Type == PyTokenIdentifier {
+			n.alias = tokens[i+1].Value
+			n.end = tokens[i+1]
+			i += 2
+		}
+		names = append(names, n)
+		end = n.end
+
+		if i >= len(tokens) || tokens[i].Type != PyTokenComma {
+			break
+		}
+		i++
+	}
+
+	if paren {
+		skipNewlines()
+		if i < len(tokens) && tokens[i].Type == PyTokenRParen {
+			end = tokens[i]
+			i++
+		}
+	}
+	return names, end, i
+}
+
+// PyDefinition is a function or class. exported is set for the module
+// level names other modules import: those listed in __all__ or, without
+// it, those not starting with an underscore. member is set for functions
+// and classes declared directly in a class body.
+type PyDefinition struct {
+	name     string
+	defType  string
+	line     int
+	column   int
+	exported bool
+	member   bool
+}
+
+func FindPythonDefinitions(content string) []PyDefinition {
+	t := NewPyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var defs []PyDefinition
+	all := pythonAllNames(tokens)
+	lineStart := 0
+	// blocks are the definitions enclosing the current line, innermost
+	// last, with the indentation of the line declaring them.
+	type block struct {
+		class  bool
+		indent int
+	}
+	var blocks []block
+
+	for i := 0; i < len(tokens); i++ {
+		if i > 0 && tokens[i-1].Type == PyTokenNewline {
+			lineStart = i
+		}
+		var defType string
+		switch tokens[i].Type {
+		case PyTokenDef:
+			defType = "function"
+		case PyTokenClass:
+			defType = "class"
+		default:
+			continue
+		}
+		if i+1 >= len(tokens) || tokens[i+1].Type != PyTokenIdentifier {
+			continue
+		}
+		name := tokens[i+1].Value
+		indent := tokens[lineStart].Column
+		exported := indent == 1 && !strings.HasPrefix(name, "_")
+		if all != nil {
+			exported = indent == 1 && all[name]
+		}
+		for len(blocks) > 0 && blocks[len(blocks)-1].indent >= indent {
+			blocks = blocks[:len(blocks)-1]
+		}
+		defs = append(defs, PyDefinition{
+			name:     name,
+			defType:  defType,
+			line:     tokens[i+1].Line,
+			column:   tokens[i+1].Column,
+			exported: exported,
+			member:   len(blocks) > 0 && blocks[len(blocks)-1].class,
+		})
+		blocks = append(blocks, block{class: defType == "class", indent: indent})
+	}
+
+	return defs
+}
+
+// pythonAllNames returns the names a module level __all__ assignment
+// lists, or nil when the module has none.
+func pythonAllNames(tokens []PyToken) map[string]bool {
+	var names map[string]bool
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].Type != PyTokenIdentifier || tokens[i].Value != "__all__" || tokens[i].Column != 1 || tokens[i+1].Type != PyTokenEquals {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]bool)
+		}
+		// The list ends with the next statement at the start of a line.
+		for i += 2; i < len(tokens) && tokens[i].Type != PyTokenEOF; i++ {
+			if tokens[i].Column == 1 && tokens[i-1].Type == PyTokenNewline {
+				i--
+				break
+			}
+			if tokens[i].Type == PyTokenString {
+				names[strings.Trim(tokens[i].Value, "\"'")] = true
+			}
+		}
+	}
+	return names
+}
+
+func FindPythonParameters(content, filename string) []CodeIssue {
+	t := NewPyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var params []CodeIssue
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == PyTokenDef && i+1 < len(tokens) && tokens[i+1].Type == PyTokenIdentifier {
+			parenCount := 0
+			paramStart := -1
+			for j := i + 2; j < len(tokens); j++ {
+				if tokens[j].Type == PyTokenLParen {
+					if parenCount == 0 {
+						paramStart = j + 1
+					}
+					parenCount++
+				}
+				if tokens[j].Type == PyTokenRParen {
+					parenCount--
+					if parenCount == 0 {
+						break
+					}
+				}
+			}
+
+			if paramStart > 0 && paramStart < len(tokens) {
+				for j := paramStart; j < len(tokens) && tokens[j].Type != PyTokenRParen; j++ {
+					if tokens[j].Type == PyTokenIdentifier && j+1 < len(tokens) && (tokens[j+1].Type == PyTokenComma || tokens[j+1].Type == PyTokenRParen) {
+						paramName := tokens[j].Value
+						if paramName != "" && paramName != "self" && paramName != "cls" {
+							params = append(params, CodeIssue{
+								Line:      tokens[j].Line,
+								Column:    tokens[j].Column,
+								EndLine:   tokens[j].Line,
+								EndColumn: tokens[j].Column + utf16Len(paramName),
+								Text:      "parameter " + paramName,
+								Kind:      "parameter",
+								Symbol:    paramName,
+								File:      filename,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return params
+}
+
+func FindUsedPythonNames(content string) map[string]int {
+	t := NewPyTokenizer(content)
+	tokens := t.Tokenize()
+
+	counts := make(map[string]int)
+	reserved := map[string]bool{
+		"import": true, "from": true, "def": true, "class": true,
+		"return": true, "if": true, "elif": true, "else": true,
+		"for": true, "while": true, "try": true, "except": true,
+		"finally": true, "with": true, "as": true, "pass": true,
+		"break": true, "continue": true, "True": true, "False": true,
+		"None": true, "and": true, "or": true, "not": true,
+		"in": true, "is": true, "lambda": true, "yield": true,
+		"global": true, "nonlocal": true, "assert": true, "raise": true,
+	}
+
+	inImport := false
+	depth := 0
+
+	for _, tok := range tokens {
+		if tok.Type == PyTokenImport || tok.Type == PyTokenFrom {
+			inImport = true
+			continue
+		}
+		if inImport {
+			switch tok.Type {
+			case PyTokenLParen:
+				depth++
+			case PyTokenRParen:
+				depth--
+			case PyTokenNewline:
+				if depth <= 0 {
+					inImport = false
+					depth = 0
+				}
+				continue
+			}
+		}
+
+		if tok.Type == PyTokenIdentifier && !reserved[tok.Value] {
+			if inImport {
+				continue
+			}
+			counts[tok.Value]++
+		}
+	}
+
+	return counts
+}
+
+func init() {
+	RegisterLanguage(pythonAnalyzer{})
+}
+
+type pythonAnalyzer struct{}
+
+func (pythonAnalyzer) Language() Language { return LangPython }
+
+func (pythonAnalyzer) Extensions() []string { return []string{".py"} }
+
+func (pythonAnalyzer) Comments() CommentSyntax { return CommentSyntax{Line: []string{"#"}} }
+
+func (pythonAnalyzer) Identifiers(code string) map[string]bool {
+	return nameSet(FindUsedPythonNames(code))
+}
+
+func (pythonAnalyzer) EmptyStatement() string { return "pass" }
+
+func (pythonAnalyzer) UnusedParameterName(name string) string { return "_" + name }
+
+func (pythonAnalyzer) FunctionKeyword() string { return "def" }
+
+func (pythonAnalyzer) StandaloneDefinitions() bool { return false }
+
+func (pythonAnalyzer) Parse(content, filename string) ParsedFile {
+	var parsed ParsedFile
+
+	for _, imp := range FindPythonImports(content) {
+		stmt := Span{
+			Line:      imp.start.Line,
+			Column:    imp.start.Column,
+			EndLine:   imp.end.Line,
+			EndColumn: imp.end.Column + utf16Len(imp.end.Value),
+		}
+		for _, n := range imp.names {
+			source := imp.module
+			if source == "" {
+				source = n.name
+			}
+			parsed.Imports = append(parsed.Imports, Import{
+				Name:      n.bound(imp.module != ""),
+				File:      filename,
+				Line:      n.start.Line,
+				Column:    n.start.Column,
+				EndLine:   n.end.Line,
+				EndColumn: n.end.Column + utf16Len(n.end.Value),
+				Source:    source,
+				Text:      n.text(imp.module),
+				Statement: stmt,
+			})
+		}
+	}
+
+	for _, d := range FindPythonDefinitions(content) {
+		parsed.Definitions = append(parsed.Definitions, Definition{
+			Name:     d.name,
+			Type:     d.defType,
+			Line:     d.line,
+			Column:   d.column,
+			File:     filename,
+			Exported: d.exported,
+			Member:   d.member,
+		})
+	}
+
+	parsed.Parameters = FindPythonParameters(content, filename)
+	parsed.Code = blankStatements(content, parsed.Imports)
+	return parsed
+}
+
+// IsTestFile reports whether filename is a test module pytest collects,
+// test_*.py or *_test.py.
+func (pythonAnalyzer) IsTestFile(filename string) bool {
+	base := path.Base(slashPath(filename))
+	return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py")
+}
+
+// ResolveImports resolves modules to their file or package, relative to
+// the importing file for relative imports and to any directory otherwise,
+// since the roots of sys.path are unknown. The name of a from import may
+// be a submodule, and importing a module also runs the __init__.py of
+// every package on its path.
+func (pythonAnalyzer) ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string {
+	dir := path.Dir(indexPath(file.Filename))
+	var deps []string
+	for _, imp := range parsed.Imports {
+		deps = append(deps, resolvePythonModule(ix, dir, imp.Source)...)
+		i := strings.Index(imp.Text, " import ")
+		if !strings.HasPrefix(imp.Text, "from ") || i < 0 {
+			continue
+		}
+		if fields := strings.Fields(imp.Text[i+len(" import "):]); len(fields) > 0 {
+			submodule := imp.Source + "." + fields[0]
+			if strings.TrimLeft(imp.Source, ".") == "" {
+				submodule = imp.Source + fields[0]
+			}
+			deps = append(deps, resolvePythonModule(ix, dir, submodule)...)
+		}
+	}
+	return deps
+}
+
+// resolvePythonModule returns the files of the packages on the path of
+// module and of the module itself, a leading dot of which makes it
+// relative to dir.
+func resolvePythonModule(ix *FileIndex, dir, module string) []string {
+	rest := strings.TrimLeft(module, ".")
+	find := ix.Find
+	if level := len(module) - len(rest); level > 0 {
+		base := dir
+		for i := 1; i < level; i++ {
+			base = path.Dir(base)
+		}
+		find = func(p string) []string {
+			if filename, ok := ix.File(path.Join(base, p)); ok {
+				return []string{filename}
+			}
+			return nil
+		}
+	}
+	if rest == "" {
+		return find("__init__.py")
+	}
+
+	var files []string
+	parts := strings.Split(rest, ".")
+	for i := 1; i <= len(parts); i++ {
+		files = append(files, find(strings.Join(parts[:i], "/")+"/__init__.py")...)
+	}
+	return append(files, find(strings.Join(parts, "/")+".py")...)
+}
+
+// UsedLocally relies on FindUsedPythonNames skipping import statements, so an
+// imported name is used as soon as it appears once while definitions and
+// parameters need a second occurrence besides their declaration.
+func (pythonAnalyzer) UsedLocally(content string, parsed ParsedFile) map[string]bool {
+	counts := FindUsedPythonNames(content)
+	used := make(map[string]bool)
+	for _, imp := range parsed.Imports {
+		for _, name := range importNames(imp) {
+			if counts[name] > 0 {
+				used[name] = true
+			}
+		}
+	}
+	for _, d := range parsed.Definitions {
+		if counts[d.Name] > 1 {
+			used[d.Name] = true
+		}
+	}
+	for _, p := range parsed.Parameters {
+		if counts[p.Symbol] > 1 {
+			used[p.Symbol] = true
+		}
+	}
+	return used
+}
@@ -0,0 +1,253 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BuildAnalysisResult turns a parsed file into issues. A name counts as
+// used when la reports a local reference or usedNames contains
+// "name@filename", the key AnalyzeWorkspace records for references found in
+// other files. Parameters are only ever checked locally. Findings disabled
+// by cfg, which may be nil, or covered by a suppression comment are
+// dropped. Exports and Files are left empty; they depend on the workspace,
+// see exports.go and deadfiles.go. Unused imports come with a Fix removing
+// them, see importFixes, and unused parameters with one renaming them, see
+// paramfix.go. Issue IDs are stable across scans, see issueID; a finding
+// reported twice for the same line is kept once.
+func BuildAnalysisResult(la LanguageAnalyzer, file AnalyzeFile, parsed ParsedFile, usedNames map[string]bool, cfg *Config) AnalysisResult {
+	return buildAnalysisResult(la, file, parsed, usedNames, cfg, "")
+}
+
+// kindAliases maps definition types parsers report under another name onto
+// the shared kinds.
+var kindAliases = map[string]string{"const": KindConstant}
+
+// definitionKind returns the Kind of the findings of def. Functions
+// declared in a class body are methods and methods declared outside of one,
+// such as top-level Ruby defs, are functions.
+func definitionKind(def Definition) string {
+	kind := def.Type
+	if alias, ok := kindAliases[kind]; ok {
+		kind = alias
+	}
+	switch {
+	case kind == KindFunction && def.Member:
+		return KindMethod
+	case kind == KindMethod && !def.Member:
+		return KindFunction
+	}
+	return kind
+}
+
+// buildAnalysisResult is BuildAnalysisResult for a file of the workspace
+// in directory root, see issueBuilder.
+func buildAnalysisResult(la LanguageAnalyzer, file AnalyzeFile, parsed ParsedFile, usedNames map[string]bool, cfg *Config, root string) AnalysisResult {
+	rules := cfg.rulesFor(root, file.Filename, la.Language())
+	if rules.ignored {
+		return AnalysisResult{Imports: []CodeIssue{}, Variables: []CodeIssue{}, Parameters: []CodeIssue{}, Exports: []CodeIssue{}, Files: []CodeIssue{}}
+	}
+
+	localUsed := la.UsedLocally(file.Content, parsed)
+	isUsed := func(name string) bool {
+		return localUsed[name] || usedNames[name+"@"+file.Filename]
+	}
+	ids := newIssueBuilder(la, file, root)
+	sup := parseSuppressions(file.Content, la.Comments())
+
+	unused := make(map[int]bool)
+	for i, imp := range parsed.Imports {
+		names := importNames(imp)
+		if len(names) == 0 || !rules.imports {
+			continue
+		}
+
+		allUnused := true
+		for _, name := range names {
+			if isUsed(name) || rules.ignoresName(name) {
+				allUnused = false
+				break
+			}
+		}
+		if allUnused && !sup.suppressed(imp.Line, "import") && !sup.suppressed(imp.Statement.Line, "import") {
+			unused[i] = true
+		}
+	}
+	fixes := importFixes(la, file, parsed.Imports, unused)
+
+	unusedImports := []CodeIssue{}
+	for i, imp := range parsed.Imports {
+		if !unused[i] {
+			continue
+		}
+		text := imp.Text
+		if text == "" {
+			text = "import " + imp.Name
+		}
+		at := CodeIssue{
+			Line:       imp.Line,
+			Column:     imp.Column,
+			EndLine:    imp.EndLine,
+			EndColumn:  imp.EndColumn,
+			Text:       text,
+			Kind:       KindImport,
+			Symbol:     imp.Name,
+			Confidence: imp.Confidence,
+			Fix:        fixes[i],
+		}
+		if issue, ok := ids.issue("import", ids.locate(at, importNames(imp)[0])); ok {
+			unusedImports = append(unusedImports, issue)
+		}
+	}
+
+	unusedVars := []CodeIssue{}
+	for _, def := range parsed.Definitions {
+		if def.Name == "" || !rules.variables || (rules.entryPoint && def.Exported) || rules.ignoresName(def.Name) {
+			continue
+		}
+		kind := definitionKind(def)
+		if isUsed(def.Name) || sup.suppressed(def.Line, "variable", kind) {
+			continue
+		}
+		member := def.Name[strings.LastIndex(def.Name, ".")+1:]
+		at := CodeIssue{
+			Line:   def.Line,
+			Column: def.Column,
+			Text:   kind + " " + def.Name,
+			Kind:   kind,
+			Symbol: def.Name,
+		}
+		if issue, ok := ids.issue("variable", ids.locate(at, member)); ok {
+			unusedVars = append(unusedVars, issue)
+		}
+	}
+
+	unusedParams := []CodeIssue{}
+	for _, p := range parsed.Parameters {
+		name := p.Symbol
+		if name == "" || !rules.parameters || rules.ignoresName(name) {
+			continue
+		}
+		if localUsed[name] || followsUnusedIdiom(la, name) || sup.suppressed(p.Line, "parameter") {
+			continue
+		}
+		p.Kind = KindParameter
+		p.Text = "parameter " + name
+		if issue, ok := ids.issue("parameter", ids.locate(p, name)); ok {
+			issue.Fix = parameterRenameFix(la, file, issue)
+			unusedParams = append(unusedParams, issue)
+		}
+	}
+
+	return AnalysisResult{
+		Imports:    unusedImports,
+		Variables:  unusedVars,
+		Parameters: unusedParams,
+		Exports:    []CodeIssue{},
+		Files:      []CodeIssue{},
+	}
+}
+
+// issueBuilder completes the issues of one file. Findings that share an
+// issueID on different lines, such as two identical declarations, get an
+// ordinal suffix in the order they are reported; a repeat on the same line
+// is a duplicate. IDs are derived from path, the filename relative to the
+// root of the workspace.
+type issueBuilder struct {
+	file       AnalyzeFile
+	path       string
+	lang       Language
+	confidence Confidence
+	lines      []string
+	seen       map[string][]int
+}
+
+func newIssueBuilder(la LanguageAnalyzer, file AnalyzeFile, root string) *issueBuilder {
+	confidence := ConfidenceMedium
+	if _, ok := la.(WorkspaceResolver); ok {
+		confidence = ConfidenceHigh
+	}
+	return &issueBuilder{
+		file:       file,
+		path:       relativePath(root, file.Filename),
+		lang:       la.Language(),
+		confidence: confidence,
+		lines:      strings.Split(strings.ReplaceAll(file.Content, "\r\n", "\n"), "\n"),
+		seen:       make(map[string][]int),
+	}
+}
+
+// issue completes at, which has its position, text, kind and symbol set,
+// for the result category "import", "variable", "parameter", "export" or
+// "file". A confidence set by the parser is kept; low confidence findings
+// are reported as info. An export shares its Kind with the finding of the
+// same definition as unused variable, so its category is part of its ID.
+func (ids *issueBuilder) issue(category string, at CodeIssue) (CodeIssue, bool) {
+	kind := at.Kind
+	if category == "export" {
+		kind = category + " " + kind
+	}
+	id := issueID(ids.path, kind, at.Symbol, lineFingerprint(ids.lines, at.Line))
+	seenLines := ids.seen[id]
+	for _, l := range seenLines {
+		if l == at.Line {
+			return CodeIssue{}, false
+		}
+	}
+	ids.seen[id] = append(seenLines, at.Line)
+	if len(seenLines) > 0 {
+		id += "-" + strconv.Itoa(len(seenLines)+1)
+	}
+
+	at.ID = id
+	at.File = ids.file.Filename
+	at.Language = ids.lang
+	at.RuleID = string(ids.lang) + "/unused-" + category
+	if at.Confidence == "" {
+		at.Confidence = ids.confidence
+	}
+	at.Severity = SeverityWarning
+	if at.Confidence == ConfidenceLow {
+		at.Severity = SeverityInfo
+	}
+	return at, true
+}
+
+// locate fills in the parts of a position a parser left out: a missing
+// column is found by searching word on the line, which falls back to the
+// whole line, and a missing end lies just after word.
+func (ids *issueBuilder) locate(at CodeIssue, word string) CodeIssue {
+	if at.Line < 1 || at.Line > len(ids.lines) {
+		return at
+	}
+	line := ids.lines[at.Line-1]
+	if at.Column == 0 {
+		if pos := indexWordInLine(line, word); pos >= 0 {
+			at.Column = utf16Column(line, pos)
+		} else {
+			trimmed := strings.TrimLeft(line, " \t")
+			at.Column = utf16Column(line, len(line)-len(trimmed))
+			at.EndLine = at.Line
+			at.EndColumn = utf16Column(line, len(strings.TrimRight(line, " \t")))
+		}
+	}
+	if at.EndLine == 0 {
+		at.EndLine = at.Line
+	}
+	if at.EndColumn == 0 {
+		at.EndColumn = at.Column + utf16Len(word)
+	}
+	return at
+}
+
+// importNames splits the comma separated names bound by a single import
+// statement, e.g. Python's "from x import a, b".
+func importNames(imp Import) []string {
+	var names []string
+	for _, name := range strings.Split(imp.Name, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// importIDs returns the IDs of the unused imports of a Python file.
+func importIDs(filename, content string) []string {
+	var ids []string
+	for _, issue := range New().Analyze(AnalyzeRequest{Filename: filename, Content: content}).Imports {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestIssueIDsAreStable(t *testing.T) {
+	const base = "import os\n\nprint(1)\n"
+	id := importIDs("app.py", base)[0]
+	if len(id) != 32 || strings.Trim(id, "0123456789abcdef") != "" {
+		t.Fatalf("ID %q is not 32 hex digits", id)
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		same     bool
+	}{
+		{"another scan", "app.py", base, true},
+		{"lines inserted above", "app.py", "\n\n# header\nimport os\n\nprint(1)\n", true},
+		{"whitespace changed", "app.py", "import   os  \n\nprint(1)\n", true},
+		{"other code changed", "app.py", "import os\n\nprint(2)\n", true},
+		{"file renamed", "main.py", base, false},
+		{"declaration changed", "app.py", "import os # noqa\n\nprint(1)\n", false},
+	}
+	for _, tt := range tests {
+		got := importIDs(tt.filename, tt.content)
+		if len(got) != 1 {
+			t.Errorf("%s: %d unused imports, want 1", tt.name, len(got))
+			continue
+		}
+		if (got[0] == id) != tt.same {
+			t.Errorf("%s: ID %s, first scan %s, want same = %v", tt.name, got[0], id, tt.same)
+		}
+	}
+}
+
+func TestIssueIDsOfIdenticalDeclarations(t *testing.T) {
+	ids := importIDs("app.py", "import os\nimport os\n")
+	if len(ids) != 2 || ids[1] != ids[0]+"-2" {
+		t.Errorf("IDs %v, want the second one suffixed with -2", ids)
+	}
+}
+
+func TestIssueColumnsCountUTF16(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		want     string // span of the only finding
+	}{
+		{"a.py", "def f(é, unused):\n    return é\n", "1:10-1:16"},
+		{"a.go", "package main\n\nfunc main() {\n\ts := \"日本\"; x := 1\n\t_ = s\n}\n", "4:13-4:14"},
+		{"a.php", "<?php\nfunction f($s = \"😀\", $unused) { return $s; }\nf(1, 2);\n", "2:24-2:30"},
+		{"a.rb", "def f(s = \"😀\", unused = 1)\n  s\nend\nf\n", "1:17-1:23"},
+		{"a.vue", "<script>\nimport { é, unused } from './x';\nexport default { data() { return { v: é } } };\n</script>\n", "2:13-2:19"},
+		{"a.svelte", "<script>\n  const s = '😀'; let unused = 1;\n</script>\n<p>{s}</p>\n", "2:23-2:29"},
+	}
+	for _, tt := range tests {
+		res := New().Analyze(AnalyzeRequest{Filename: tt.filename, Content: tt.content})
+		var got []string
+		for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters} {
+			for _, issue := range issues {
+				got = append(got, fmt.Sprintf("%d:%d-%d:%d", issue.Line, issue.Column, issue.EndLine, issue.EndColumn))
+			}
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s: findings at %v, want one at %s", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestDefinitionKindsAreShared(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		want     []string // "kind symbol" of every unused definition or export
+	}{
+		{
+			"a.py",
+			"class _A:\n    def _m(self):\n        def inner():\n            pass\n\n    class _B:\n        pass\n\ndef _f():\n    pass\n",
+			[]string{"class _A", "method _m", "function inner", "class _B", "function _f"},
+		},
+		{
+			"a.php",
+			"<?php\nconst A = 1;\nfunction f() {}\nclass C {\n    const B = 2;\n    private function m() {}\n}\n",
+			[]string{"constant A", "function f", "class C", "constant B", "method m"},
+		},
+		{
+			"a.rb",
+			"def helper\nend\n\nmodule M\n  def self.build\n  end\n\n  class C\n    def run\n    end\n  end\nend\n",
+			[]string{"function helper", "module M", "method build", "class C", "method run"},
+		},
+		{
+			"a.go",
+			"package main\n\nconst c = 1\n\ntype t struct{ f int }\n\nfunc (t) m() {}\n\nfunc fn() {}\n\nfunc main() {}\n",
+			[]string{"constant c", "field t.f", "method t.m", "function fn"},
+		},
+	}
+	for _, tt := range tests {
+		res := New().AnalyzeWorkspace(WorkspaceAnalyzeRequest{Files: []AnalyzeFile{{Filename: tt.filename, Content: tt.content}}}).Results[tt.filename]
+		var got []string
+		for _, issues := range [][]CodeIssue{res.Variables, res.Exports} {
+			for _, issue := range issues {
+				got = append(got, issue.Kind+" "+issue.Symbol)
+			}
+		}
+		sort.Strings(got)
+		sort.Strings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.filename, got, tt.want)
+		}
+	}
+}
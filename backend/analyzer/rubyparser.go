@@ -0,0 +1,556 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type RubyTokenType int
+
+const (
+	RubyTokenRequire RubyTokenType = iota
+	RubyTokenRequireRelative
+	RubyTokenDef
+	RubyTokenClass
+	RubyTokenModule
+	RubyTokenIdentifier
+	RubyTokenString
+	RubyTokenLParen
+	RubyTokenRParen
+	RubyTokenComma
+	RubyTokenDot
+	RubyTokenNewline
+	RubyTokenEOF
+	RubyTokenUnknown
+)
+
+// RubyToken is a token of Ruby source. Column is only set for identifiers
+// and strings.
+type RubyToken struct {
+	Type   RubyTokenType
+	Value  string
+	Line   int
+	Column int
+}
+
+type RubyTokenizer struct {
+	content   string
+	pos       int
+	line      int
+	lineStart int
+	tokens    []RubyToken
+}
+
+func NewRubyTokenizer(content string) *RubyTokenizer {
+	return &RubyTokenizer{
+		content: content,
+		pos:     0,
+		line:    1,
+		tokens:  make([]RubyToken, 0),
+	}
+}
+
+func (t *RubyTokenizer) peek() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(t.content[t.pos:])
+	return r
+}
+
+func (t *RubyTokenizer) next() rune {
+	if t.pos >= len(t.content) {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(t.content[t.pos:])
+	t.pos += size
+	if r == '\n' {
+		t.line++
+		t.lineStart = t.pos
+	}
+	return r
+}
+
+// column returns the column of the byte offset pos on the current line.
+func (t *RubyTokenizer) column(pos int) int {
+	return utf16Column(t.content[t.lineStart:], pos-t.lineStart)
+}
+
+func (t *RubyTokenizer) Tokenize() []RubyToken {
+	inString := false
+	var stringChar rune
+	var stringStart int
+	var stringLine int
+	var stringColumn int
+
+	for t.pos < len(t.content) {
+		ch := t.peek()
+
+		if inString {
+			if ch == stringChar && t.pos > 0 && t.content[t.pos-1] != '\\' {
+				t.next()
+				inString = false
+				t.tokens = append(t.tokens, RubyToken{Type: RubyTokenString, Value: t.content[stringStart:t.pos], Line: stringLine, Column: stringColumn})
+			} else {
+				t.next()
+			}
+			continue
+		}
+
+		if ch == 0 {
+			break
+		}
+
+		if ch == '\n' {
+			t.next()
+			t.tokens = append(t.tokens, RubyToken{Type: RubyTokenNewline, Value: "\n", Line: t.line})
+			continue
+		}
+
+		if unicode.IsSpace(ch) {
+			t.next()
+			continue
+		}
+
+		if ch == '#' {
+			for t.peek() != '\n' && t.peek() != 0 {
+				t.next()
+			}
+			continue
+		}
+
+		if ch == '"' || ch == '\'' {
+			inString = true
+			stringChar = ch
+			stringStart = t.pos
+			stringLine = t.line
+			stringColumn = t.column(t.pos)
+			t.next()
+			continue
+		}
+
+		if unicode.IsLetter(ch) || ch == '_' {
+			t.tokens = append(t.tokens, t.readIdentifier())
+			continue
+		}
+
+		if unicode.IsDigit(ch) {
+			t.next()
+			for unicode.IsDigit(t.peek()) {
+				t.next()
+			}
+			continue
+		}
+
+		switch ch {
+		case '(':
+			t.next()
+			t.tokens = append(t.tokens, RubyToken{Type: RubyTokenLParen, Value: "(", Line: t.line})
+		case ')':
+			t.next()
+			t.tokens = append(t.tokens, RubyToken{Type: RubyTokenRParen, Value: ")", Line: t.line})
+		case ',':
+			t.next()
+			t.tokens = append(t.tokens, RubyToken{Type: RubyTokenComma, Value: ",", Line: t.line})
+		case '.':
+			t.next()
+			t.tokens = append(t.tokens, RubyToken{Type: RubyTokenDot, Value: ".", Line: t.line})
+		default:
+			t.next()
+		}
+	}
+
+	t.tokens = append(t.tokens, RubyToken{Type: RubyTokenEOF, Value: "", Line: t.line})
+	return t.tokens
+}
+
+func (t *RubyTokenizer) readIdentifier() RubyToken {
+	start := t.pos
+	line := t.line
+	column := t.column(start)
+	for unicode.IsLetter(t.peek()) || unicode.IsDigit(t.peek()) || t.peek() == '_' {
+		t.next()
+	}
+	value := t.content[start:t.pos]
+
+	switch value {
+	case "require":
+		return RubyToken{Type: RubyTokenRequire, Value: value, Line: line, Column: column}
+	case "require_relative":
+		return RubyToken{Type: RubyTokenRequireRelative, Value: value, Line: line, Column: column}
+	case "def":
+		return RubyToken{Type: RubyTokenDef, Value: value, Line: line, Column: column}
+	case "class":
+		return RubyToken{Type: RubyTokenClass, Value: value, Line: line, Column: column}
+	case "module":
+		return RubyToken{Type: RubyTokenModule, Value: value, Line: line, Column: column}
+	default:
+		return RubyToken{Type: RubyTokenIdentifier, Value: value, Line: line, Column: column}
+	}
+}
+
+// RubyImportItem is a require statement; keyword is the require token and
+// pathToken its argument.
+type RubyImportItem struct {
+	name      string
+	path      string
+	line      int
+	text      string
+	keyword   RubyToken
+	pathToken RubyToken
+}
+
+func FindRubyImports(content string) []RubyImportItem {
+	t := NewRubyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var imports []RubyImportItem
+
+	var i int
+	for i < len(tokens) {
+		if (tokens[i].Type == RubyTokenRequire || tokens[i].Type == RubyTokenRequireRelative) && i+1 < len(tokens) {
+			line := tokens[i].Line
+
+			var path string
+			if tokens[i+1].Type == RubyTokenString {
+				path = strings.Trim(tokens[i+1].Value, `"'`)
+			} else if tokens[i+1].Type == RubyTokenIdentifier {
+				path = tokens[i+1].Value
+			}
+
+			if path == "" {
+				i++
+				continue
+			}
+
+			name := path
+			if strings.Contains(path, "/") {
+				parts := strings.Split(path, "/")
+				name = parts[len(parts)-1]
+			}
+			name = strings.TrimSuffix(name, ".rb")
+
+			text := "require"
+			if tokens[i].Type == RubyTokenRequireRelative {
+				text = "require_relative"
+			}
+			if tokens[i+1].Type == RubyTokenString {
+				text += " " + tokens[i+1].Value
+			} else {
+				text += " " + path
+			}
+
+			imports = append(imports, RubyImportItem{
+				name:      name,
+				path:      path,
+				line:      line,
+				text:      text,
+				keyword:   tokens[i],
+				pathToken: tokens[i+1],
+			})
+			i += 2
+			continue
+		}
+
+		i++
+	}
+
+	return imports
+}
+
+// RubyDefinition is a method, class or module. exported is set unless a
+// method is made private or protected, by a "private" line starting a
+// section, a "private def" or a "private :name" list. Sections end with
+// the next class or module. member is set for methods declared in a class
+// or module body and for singleton methods such as "def self.name".
+type RubyDefinition struct {
+	name     string
+	defType  string
+	line     int
+	column   int
+	exported bool
+	member   bool
+}
+
+func FindRubyDefinitions(content string) []RubyDefinition {
+	t := NewRubyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var defs []RubyDefinition
+	hiddenSection, hiddenNext := false, false
+	hidden := make(map[string]bool)
+	// blocks are the definitions enclosing the current line, innermost
+	// last, with the indentation of the line declaring them.
+	type block struct {
+		container bool
+		indent    int
+	}
+	var blocks []block
+	indent := 0
+	enter := func(container bool) (member bool) {
+		for len(blocks) > 0 && blocks[len(blocks)-1].indent >= indent {
+			blocks = blocks[:len(blocks)-1]
+		}
+		member = len(blocks) > 0 && blocks[len(blocks)-1].container
+		blocks = append(blocks, block{container: container, indent: indent})
+		return member
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		lineStart := i == 0 || tokens[i-1].Type == RubyTokenNewline
+		if lineStart {
+			indent = tokens[i].Column
+		}
+		if lineStart && tokens[i].Type == RubyTokenIdentifier && i+1 < len(tokens) {
+			switch tokens[i].Value {
+			case "private", "protected", "public":
+				hide := tokens[i].Value != "public"
+				switch next := tokens[i+1]; next.Type {
+				case RubyTokenNewline, RubyTokenEOF:
+					hiddenSection = hide
+				case RubyTokenDef:
+					hiddenNext = hide
+				default:
+					for j := i + 1; j < len(tokens) && tokens[j].Type != RubyTokenNewline; j++ {
+						if tokens[j].Type == RubyTokenIdentifier {
+							hidden[tokens[j].Value] = hide
+						}
+					}
+				}
+			}
+		}
+
+		if tokens[i].Type == RubyTokenDef && i+1 < len(tokens) {
+			if tokens[i+1].Type == RubyTokenIdentifier {
+				member := enter(false)
+				name := tokens[i+1]
+				// "def self.name" declares a singleton method.
+				if i+3 < len(tokens) && tokens[i+2].Type == RubyTokenDot && tokens[i+3].Type == RubyTokenIdentifier {
+					name = tokens[i+3]
+					member = true
+				}
+				defs = append(defs, RubyDefinition{
+					name:     name.Value,
+					defType:  "method",
+					line:     name.Line,
+					column:   name.Column,
+					exported: !hiddenSection && !hiddenNext,
+					member:   member,
+				})
+			}
+			hiddenNext = false
+		}
+		if tokens[i].Type == RubyTokenClass && i+1 < len(tokens) && tokens[i+1].Type == RubyTokenIdentifier {
+			hiddenSection = false
+			defs = append(defs, RubyDefinition{
+				name:     tokens[i+1].Value,
+				defType:  "class",
+				line:     tokens[i+1].Line,
+				column:   tokens[i+1].Column,
+				exported: true,
+				member:   enter(true),
+			})
+		}
+		if tokens[i].Type == RubyTokenModule && i+1 < len(tokens) && tokens[i+1].Type == RubyTokenIdentifier {
+			hiddenSection = false
+			defs = append(defs, RubyDefinition{
+				name:     tokens[i+1].Value,
+				defType:  "module",
+				line:     tokens[i+1].Line,
+				column:   tokens[i+1].Column,
+				exported: true,
+				member:   enter(true),
+			})
+		}
+	}
+
+	for i := range defs {
+		if defs[i].defType == "method" && hidden[defs[i].name] {
+			defs[i].exported = false
+		}
+	}
+	return defs
+}
+
+func FindRubyParameters(content, filename string) []CodeIssue {
+	t := NewRubyTokenizer(content)
+	tokens := t.Tokenize()
+
+	var params []CodeIssue
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == RubyTokenDef && i+1 < len(tokens) && tokens[i+1].Type == RubyTokenIdentifier {
+			parenCount := 0
+			paramStart := -1
+			for j := i + 2; j < len(tokens); j++ {
+				if tokens[j].Type == RubyTokenLParen {
+					if parenCount == 0 {
+						paramStart = j + 1
+					}
+					parenCount++
+				}
+				if tokens[j].Type == RubyTokenRParen {
+					parenCount--
+					if parenCount == 0 {
+						break
+					}
+				}
+			}
+
+			if paramStart > 0 && paramStart < len(tokens) {
+				for j := paramStart; j < len(tokens) && tokens[j].Type != RubyTokenRParen; j++ {
+					if tokens[j].Type == RubyTokenIdentifier && j+1 < len(tokens) && (tokens[j+1].Type == RubyTokenComma || tokens[j+1].Type == RubyTokenRParen) {
+						paramName := tokens[j].Value
+						if paramName != "" && paramName != "self" && paramName != "cls" {
+							params = append(params, CodeIssue{
+								Line:      tokens[j].Line,
+								Column:    tokens[j].Column,
+								EndLine:   tokens[j].Line,
+								EndColumn: tokens[j].Column + utf16Len(paramName),
+								Text:      "parameter " + paramName,
+								Kind:      "parameter",
+								Symbol:    paramName,
+								File:      filename,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return params
+}
+
+func FindUsedRubyNames(content string) map[string]int {
+	t := NewRubyTokenizer(content)
+	tokens := t.Tokenize()
+
+	counts := make(map[string]int)
+	reserved := map[string]bool{
+		"require": true, "require_relative": true, "def": true, "class": true,
+		"module": true, "end": true, "if": true, "elsif": true, "else": true,
+		"unless": true, "case": true, "when": true, "while": true, "until": true,
+		"for": true, "do": true, "begin": true, "rescue": true, "ensure": true,
+		"raise": true, "return": true, "yield": true, "break": true, "next": true,
+		"redo": true, "retry": true, "self": true, "super": true, "true": true,
+		"false": true, "nil": true, "and": true, "or": true, "not": true,
+		"in": true, "then": true, "alias": true, "defined": true, "lambda": true,
+	}
+
+	for _, tok := range tokens {
+		if tok.Type == RubyTokenIdentifier && !reserved[tok.Value] {
+			counts[tok.Value]++
+		}
+	}
+
+	return counts
+}
+
+func init() {
+	RegisterLanguage(rubyAnalyzer{})
+}
+
+type rubyAnalyzer struct{}
+
+func (rubyAnalyzer) Language() Language { return LangRuby }
+
+func (rubyAnalyzer) Extensions() []string { return []string{".rb"} }
+
+func (rubyAnalyzer) Comments() CommentSyntax {
+	return CommentSyntax{Line: []string{"#"}, BlockStart: "=begin", BlockEnd: "=end"}
+}
+
+func (rubyAnalyzer) StandaloneDefinitions() bool { return false }
+
+func (rubyAnalyzer) Identifiers(code string) map[string]bool {
+	return nameSet(FindUsedRubyNames(code))
+}
+
+func (rubyAnalyzer) UnusedParameterName(name string) string { return "_" + name }
+
+func (rubyAnalyzer) FunctionKeyword() string { return "def" }
+
+func (rubyAnalyzer) Parse(content, filename string) ParsedFile {
+	var parsed ParsedFile
+
+	for _, imp := range FindRubyImports(content) {
+		parsed.Imports = append(parsed.Imports, Import{
+			Name:      imp.name,
+			File:      filename,
+			Line:      imp.line,
+			Column:    imp.pathToken.Column,
+			EndLine:   imp.pathToken.Line,
+			EndColumn: imp.pathToken.Column + utf16Len(imp.pathToken.Value),
+			Source:    imp.path,
+			Text:      imp.text,
+			Statement: Span{
+				Line:      imp.keyword.Line,
+				Column:    imp.keyword.Column,
+				EndLine:   imp.pathToken.Line,
+				EndColumn: imp.pathToken.Column + utf16Len(imp.pathToken.Value),
+			},
+			// The constant a required file defines is guessed from its path,
+			// and requiring it may be needed for its side effects alone.
+			Confidence: ConfidenceLow,
+		})
+	}
+
+	for _, d := range FindRubyDefinitions(content) {
+		parsed.Definitions = append(parsed.Definitions, Definition{
+			Name:     d.name,
+			Type:     d.defType,
+			Line:     d.line,
+			Column:   d.column,
+			File:     filename,
+			Exported: d.exported,
+			Member:   d.member,
+		})
+	}
+
+	parsed.Parameters = FindRubyParameters(content, filename)
+	parsed.Code = blankLines(content, func(trimmed string) bool {
+		return strings.HasPrefix(trimmed, "require ") || strings.HasPrefix(trimmed, "require_relative ")
+	})
+	return parsed
+}
+
+// IsTestFile reports whether filename is a spec of RSpec or a test of
+// Minitest, *_spec.rb, *_test.rb or test_*.rb.
+func (rubyAnalyzer) IsTestFile(filename string) bool {
+	base := path.Base(slashPath(filename))
+	return strings.HasSuffix(base, "_spec.rb") || strings.HasSuffix(base, "_test.rb") || strings.HasPrefix(base, "test_")
+}
+
+// ResolveImports resolves require_relative against the requiring file and
+// require against any directory, adding the .rb extension when missing.
+func (rubyAnalyzer) ResolveImports(file AnalyzeFile, parsed ParsedFile, ix *FileIndex) []string {
+	dir := path.Dir(indexPath(file.Filename))
+	var deps []string
+	for _, imp := range parsed.Imports {
+		source := imp.Source
+		if !strings.HasSuffix(source, ".rb") {
+			source += ".rb"
+		}
+		if strings.HasPrefix(imp.Text, "require_relative") {
+			if filename, ok := ix.File(path.Join(dir, source)); ok {
+				deps = append(deps, filename)
+			}
+			continue
+		}
+		deps = append(deps, ix.Find(source)...)
+	}
+	return deps
+}
+
+func (rubyAnalyzer) UsedLocally(content string, parsed ParsedFile) map[string]bool {
+	counts := FindUsedRubyNames(content)
+	used := make(map[string]bool)
+	for name, n := range counts {
+		if n > 1 {
+			used[name] = true
+		}
+	}
+	return used
+}
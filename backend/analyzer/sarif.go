@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SARIF 2.1.0 export of workspace results, for code scanning dashboards and
+// other SARIF viewers. Every combination of finding kind and language is a
+// rule, e.g. "python/unused-import", and issue IDs are passed on as
+// fingerprints so that results can be tracked across runs.
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	// SARIFToolName is the driver name written to SARIF logs.
+	SARIFToolName = "unused-code-analyzer"
+	sarifToolURI  = "https://github.com/selcuksarikoz/unused-code-analyzer"
+
+	sarifFingerprint = "unusedCodeIssue/v1"
+	sarifRootBase    = "%SRCROOT%"
+)
+
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool SARIFTool `json:"tool"`
+	// OriginalURIBaseIDs locates sarifRootBase, which artifact URIs are
+	// relative to, when the absolute root is known, see SetSourceRoot.
+	OriginalURIBaseIDs map[string]SARIFArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []SARIFResult                    `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     SARIFMessage           `json:"shortDescription"`
+	DefaultConfiguration SARIFRuleConfiguration `json:"defaultConfiguration"`
+	Properties           SARIFRuleProperties    `json:"properties"`
+}
+
+type SARIFRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SARIFRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// sarifKind describes one category of AnalysisResult.
+type sarifKind struct {
+	id          string
+	name        string
+	description string
+	issues      func(AnalysisResult) []CodeIssue
+}
+
+var sarifKinds = []sarifKind{
+	{"unused-import", "UnusedImport", "Imported name is never used", func(r AnalysisResult) []CodeIssue { return r.Imports }},
+	{"unused-variable", "UnusedDefinition", "Definition is never used", func(r AnalysisResult) []CodeIssue { return r.Variables }},
+	{"unused-parameter", "UnusedParameter", "Parameter is never used", func(r AnalysisResult) []CodeIssue { return r.Parameters }},
+	{"unused-export", "UnusedExport", "Exported definition is never used by another file", func(r AnalysisResult) []CodeIssue { return r.Exports }},
+	{"unused-file", "UnusedFile", "File is not reachable from any entry point", func(r AnalysisResult) []CodeIssue { return r.Files }},
+}
+
+// NewSARIFLog converts workspace results, analyzed with root as
+// WorkspaceAnalyzeRequest.Root, into a SARIF log with a single run. Files
+// are located relative to root, as code scanning services expect. Files
+// are ordered by name and issues by line, so equal results produce equal
+// logs.
+func NewSARIFLog(result WorkspaceAnalysisResult, root string) *SARIFLog {
+	filenames := make([]string, 0, len(result.Results))
+	for filename := range result.Results {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	driver := SARIFDriver{Name: SARIFToolName, InformationURI: sarifToolURI, Rules: []SARIFRule{}}
+	ruleIndex := make(map[string]int)
+	results := []SARIFResult{}
+
+	for _, filename := range filenames {
+		lang := DetectLanguage(filename)
+		var fileResults []SARIFResult
+		for _, kind := range sarifKinds {
+			for _, issue := range kind.issues(result.Results[filename]) {
+				ruleID := issue.RuleID
+				if ruleID == "" {
+					ruleID = string(lang) + "/" + kind.id
+				}
+				index, ok := ruleIndex[ruleID]
+				if !ok {
+					index = len(driver.Rules)
+					ruleIndex[ruleID] = index
+					driver.Rules = append(driver.Rules, SARIFRule{
+						ID:                   ruleID,
+						Name:                 kind.name,
+						ShortDescription:     SARIFMessage{Text: kind.description},
+						DefaultConfiguration: SARIFRuleConfiguration{Level: "warning"},
+						Properties:           SARIFRuleProperties{Tags: []string{"maintainability", string(lang)}},
+					})
+				}
+				fileResults = append(fileResults, newSARIFResult(ruleID, index, kind, issue, relativePath(root, filename)))
+			}
+		}
+		sort.SliceStable(fileResults, func(i, j int) bool {
+			return fileResults[i].Locations[0].PhysicalLocation.Region.StartLine <
+				fileResults[j].Locations[0].PhysicalLocation.Region.StartLine
+		})
+		results = append(results, fileResults...)
+	}
+
+	log := &SARIFLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+	if isAbsPath(slashPath(root)) {
+		log.SetSourceRoot(root)
+	}
+	return log
+}
+
+// SetSourceRoot records dir, the absolute path of the root the artifact
+// URIs are relative to, in every run of l.
+func (l *SARIFLog) SetSourceRoot(dir string) {
+	uri := sarifFileURI(slashPath(dir))
+	if !strings.HasSuffix(uri, "/") {
+		uri += "/"
+	}
+	for i := range l.Runs {
+		l.Runs[i].OriginalURIBaseIDs = map[string]SARIFArtifactLocation{sarifRootBase: {URI: uri}}
+	}
+}
+
+func newSARIFResult(ruleID string, ruleIndex int, kind sarifKind, issue CodeIssue, filename string) SARIFResult {
+	res := SARIFResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(issue.Severity),
+		Message:   SARIFMessage{Text: "Unused " + strings.TrimPrefix(kind.id, "unused-") + ": " + issue.Text},
+		Locations: []SARIFLocation{{
+			PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: sarifArtifact(filename),
+				Region: SARIFRegion{
+					StartLine:   issue.Line,
+					StartColumn: issue.Column,
+					EndLine:     issue.EndLine,
+					EndColumn:   issue.EndColumn,
+				},
+			},
+		}},
+	}
+	if issue.ID != "" {
+		res.PartialFingerprints = map[string]string{sarifFingerprint: issue.ID}
+	}
+	return res
+}
+
+// sarifLevel maps a severity to a SARIF result level. Issues without a
+// severity are warnings.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// sarifArtifact turns a filename relative to the root into an artifact
+// location based on sarifRootBase. Files outside of the root keep their
+// absolute path as a file URI.
+func sarifArtifact(filename string) SARIFArtifactLocation {
+	name := slashPath(filename)
+	if isAbsPath(name) {
+		return SARIFArtifactLocation{URI: sarifFileURI(name)}
+	}
+	name = strings.TrimPrefix(path.Clean(name), "./")
+	return SARIFArtifactLocation{URI: (&url.URL{Path: name}).String(), URIBaseID: sarifRootBase}
+}
+
+// isAbsPath reports whether name, which uses "/" as separator, is an
+// absolute path on Unix or Windows.
+func isAbsPath(name string) bool {
+	return path.IsAbs(name) || (len(name) > 2 && name[1] == ':' && name[2] == '/')
+}
+
+// sarifFileURI returns the file URI of the absolute path name.
+func sarifFileURI(name string) string {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	return (&url.URL{Scheme: "file", Path: name}).String()
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSARIFLocationsAreRelativeToRoot(t *testing.T) {
+	result := WorkspaceAnalysisResult{Results: map[string]AnalysisResult{
+		"/home/ci/repo/src/my app.py": {Imports: []CodeIssue{{Symbol: "os", Line: 3, ID: "a"}}},
+		"/home/ci/repo/lib.rb":        {Parameters: []CodeIssue{{Symbol: "x", Line: 1, Severity: SeverityInfo}}},
+		"/tmp/outside.py":             {Variables: []CodeIssue{{Symbol: "y", Line: 2}}},
+	}}
+	tests := []struct {
+		root string
+		uris []string
+		base map[string]SARIFArtifactLocation
+	}{
+		{
+			root: "/home/ci/repo",
+			uris: []string{"%SRCROOT% lib.rb", "%SRCROOT% src/my%20app.py", " file:///tmp/outside.py"},
+			base: map[string]SARIFArtifactLocation{"%SRCROOT%": {URI: "file:///home/ci/repo/"}},
+		},
+		{
+			root: "/",
+			uris: []string{"%SRCROOT% home/ci/repo/lib.rb", "%SRCROOT% home/ci/repo/src/my%20app.py", "%SRCROOT% tmp/outside.py"},
+			base: map[string]SARIFArtifactLocation{"%SRCROOT%": {URI: "file:///"}},
+		},
+	}
+	for _, tt := range tests {
+		run := NewSARIFLog(result, tt.root).Runs[0]
+		var uris []string
+		for _, res := range run.Results {
+			loc := res.Locations[0].PhysicalLocation.ArtifactLocation
+			uris = append(uris, loc.URIBaseID+" "+loc.URI)
+		}
+		if !reflect.DeepEqual(uris, tt.uris) {
+			t.Errorf("root %s: artifact URIs %q, want %q", tt.root, uris, tt.uris)
+		}
+		if !reflect.DeepEqual(run.OriginalURIBaseIDs, tt.base) {
+			t.Errorf("root %s: originalUriBaseIds %v, want %v", tt.root, run.OriginalURIBaseIDs, tt.base)
+		}
+	}
+
+	// A relative root leaves the base to SetSourceRoot.
+	log := NewSARIFLog(WorkspaceAnalysisResult{Results: map[string]AnalysisResult{
+		"repo/a.py": {Imports: []CodeIssue{{Symbol: "os", Line: 1}}},
+	}}, "repo")
+	if got := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; got != "a.py" {
+		t.Errorf("relative root: artifact URI %q, want %q", got, "a.py")
+	}
+	if log.Runs[0].OriginalURIBaseIDs != nil {
+		t.Errorf("relative root: originalUriBaseIds %v, want none", log.Runs[0].OriginalURIBaseIDs)
+	}
+	log.SetSourceRoot(`C:\work\repo`)
+	if got := log.Runs[0].OriginalURIBaseIDs["%SRCROOT%"].URI; got != "file:///C:/work/repo/" {
+		t.Errorf("SetSourceRoot: base %q, want %q", got, "file:///C:/work/repo/")
+	}
+}
+
+func TestSARIFRulesAndResults(t *testing.T) {
+	result := WorkspaceAnalysisResult{Results: map[string]AnalysisResult{
+		"b.py": {
+			Imports:   []CodeIssue{{Symbol: "sys", Line: 5, ID: "b-sys", Severity: SeverityError}},
+			Variables: []CodeIssue{{Symbol: "x", Line: 2, Text: "x"}},
+		},
+		"a.py": {Imports: []CodeIssue{{Symbol: "os", Line: 1}}},
+		"c.go": {Files: []CodeIssue{{Line: 1, RuleID: "custom/rule"}}},
+	}}
+	run := NewSARIFLog(result, "").Runs[0]
+
+	var rules []string
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	if want := []string{"python/unused-import", "python/unused-variable", "custom/rule"}; !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules %v, want %v", rules, want)
+	}
+
+	type summary struct {
+		rule   string
+		index  int
+		level  string
+		line   int
+		finger string
+	}
+	var got []summary
+	for _, res := range run.Results {
+		got = append(got, summary{res.RuleID, res.RuleIndex, res.Level, res.Locations[0].PhysicalLocation.Region.StartLine, res.PartialFingerprints[sarifFingerprint]})
+	}
+	want := []summary{
+		{"python/unused-import", 0, "warning", 1, ""},
+		{"python/unused-variable", 1, "warning", 2, ""},
+		{"python/unused-import", 0, "error", 5, "b-sys"},
+		{"custom/rule", 2, "warning", 1, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results %+v, want %+v", got, want)
+	}
+	if msg := run.Results[1].Message.Text; msg != "Unused variable: x" {
+		t.Errorf("message %q, want %q", msg, "Unused variable: x")
+	}
+}
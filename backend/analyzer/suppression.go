@@ -0,0 +1,166 @@
+package analyzer
+
+import "strings"
+
+// suppressionDirective starts a comment marking findings as intentionally
+// unused: "unused-ignore" covers its own line, "unused-ignore-next-line"
+// the following one and "unused-ignore-file" the file, whose file finding
+// is on the first line. Kinds listed after it, such as in
+// "# unused-ignore import, function", limit it to those categories or
+// definition types; anything after them is a free-form reason.
+const suppressionDirective = "unused-ignore"
+
+// suppressionKinds are the kinds a directive may list.
+var suppressionKinds = map[string]bool{
+	"import": true, "variable": true, "parameter": true,
+	"function": true, "method": true, "field": true, "type": true,
+	"constant": true, "class": true, "interface": true,
+	"trait": true, "module": true, "export": true, "file": true,
+}
+
+// suppression is one directive. A nil kinds set applies to every finding.
+type suppression struct {
+	kinds map[string]bool
+}
+
+func (s suppression) matches(kinds []string) bool {
+	if s.kinds == nil {
+		return true
+	}
+	for _, kind := range kinds {
+		if s.kinds[kind] {
+			return true
+		}
+	}
+	return false
+}
+
+type suppressions struct {
+	file  []suppression
+	lines map[int][]suppression
+}
+
+// parseSuppressions collects the suppression comments of content.
+func parseSuppressions(content string, syntax CommentSyntax) *suppressions {
+	s := &suppressions{lines: make(map[int][]suppression)}
+	if !strings.Contains(content, suppressionDirective) {
+		return s
+	}
+
+	_, comments := splitComments(content, syntax)
+	for i, comment := range comments {
+		line := i + 1
+		rest := comment
+		for {
+			pos := strings.Index(rest, suppressionDirective)
+			if pos < 0 {
+				break
+			}
+			if pos > 0 && isWordChar(rest[pos-1]) {
+				rest = rest[pos+len(suppressionDirective):]
+				continue
+			}
+			rest = rest[pos+len(suppressionDirective):]
+
+			target := line
+			switch {
+			case strings.HasPrefix(rest, "-next-line"):
+				rest = rest[len("-next-line"):]
+				target = line + 1
+			case strings.HasPrefix(rest, "-file"):
+				rest = rest[len("-file"):]
+				target = 0
+			}
+			if rest != "" && isWordChar(rest[0]) {
+				continue
+			}
+
+			sup := suppression{kinds: parseSuppressionKinds(rest)}
+			if target == 0 {
+				s.file = append(s.file, sup)
+			} else {
+				s.lines[target] = append(s.lines[target], sup)
+			}
+		}
+	}
+	return s
+}
+
+// parseSuppressionKinds reads the kinds listed at the start of rest and
+// stops at the first word that is not a kind.
+func parseSuppressionKinds(rest string) map[string]bool {
+	var kinds map[string]bool
+	fields := strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ':' || r == ' ' || r == '\t'
+	})
+	for _, field := range fields {
+		kind := strings.ToLower(field)
+		if alias, ok := kindAliases[kind]; ok {
+			kind = alias
+		}
+		if !suppressionKinds[kind] {
+			break
+		}
+		if kinds == nil {
+			kinds = make(map[string]bool)
+		}
+		kinds[kind] = true
+	}
+	return kinds
+}
+
+// suppressed reports whether a finding of any of kinds on line is covered by
+// a suppression comment.
+func (s *suppressions) suppressed(line int, kinds ...string) bool {
+	for _, sup := range s.file {
+		if sup.matches(kinds) {
+			return true
+		}
+	}
+	for _, sup := range s.lines[line] {
+		if sup.matches(kinds) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// SuppressionFix returns the fix suppressing issue with an
+// "unused-ignore-next-line" comment on a new line above it, indented like
+// the line of the issue. content is the file the issue was reported for.
+// It returns nil for files of no supported language.
+func SuppressionFix(issue CodeIssue, content string) *Fix {
+	la := languageFor(issue.File)
+	if la == nil || issue.Line < 1 {
+		return nil
+	}
+	syntax := la.Comments()
+	comment := suppressionDirective + "-next-line"
+	kind := issue.Kind
+	if strings.HasSuffix(issue.RuleID, "/unused-export") {
+		kind = "export"
+	}
+	if suppressionKinds[kind] {
+		comment += " " + kind
+	}
+	switch {
+	case len(syntax.Line) > 0:
+		comment = syntax.Line[0] + " " + comment
+	case syntax.BlockStart != "":
+		comment = syntax.BlockStart + " " + comment + " " + syntax.BlockEnd
+	default:
+		return nil
+	}
+
+	src := newSourceText(content)
+	start := src.offset(issue.Line, 1)
+	indent := content[start:skipSpaceForward(content, start)]
+	return &Fix{
+		Title: "Suppress unused " + kind + " " + issue.Symbol,
+		Edits: []TextEdit{{File: issue.File, Span: src.span(start, start), NewText: indent + comment + "\n"}},
+	}
+}
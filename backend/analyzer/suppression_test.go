@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuppressionComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     []string // symbols still reported
+	}{
+		{
+			name:     "same line",
+			filename: "a.py",
+			content:  "import os  # unused-ignore\nimport sys\n",
+			want:     []string{"sys"},
+		},
+		{
+			name:     "next line",
+			filename: "a.py",
+			content:  "# unused-ignore-next-line\nimport os\nimport sys\n",
+			want:     []string{"sys"},
+		},
+		{
+			name:     "whole file",
+			filename: "a.rb",
+			content:  "# unused-ignore-file: generated code\ndef helper(x)\nend\n",
+			want:     nil,
+		},
+		{
+			name:     "limited to another kind",
+			filename: "a.py",
+			content:  "import os  # unused-ignore parameter\n",
+			want:     []string{"os"},
+		},
+		{
+			name:     "kinds followed by a reason",
+			filename: "main.go",
+			content:  "package main\n\n// unused-ignore-next-line function, parameter kept for the plugin API\nfunc hook(x int) {}\n\nfunc main() {}\n",
+			want:     nil,
+		},
+		{
+			name:     "block comment",
+			filename: "a.php",
+			content:  "<?php\n/* unused-ignore-next-line */\nuse App\\Foo;\nuse App\\Bar;\n",
+			want:     []string{"Bar"},
+		},
+		{
+			name:     "component script",
+			filename: "C.svelte",
+			content:  "<script>\n  // unused-ignore-next-line import\n  import { a } from './a';\n  import { b } from './b';\n</script>\n",
+			want:     []string{"b"},
+		},
+		{
+			name:     "directive in a string",
+			filename: "a.py",
+			content:  "import os; print('unused-ignore')\n",
+			want:     []string{"os"},
+		},
+		{
+			name:     "longer word",
+			filename: "a.py",
+			content:  "import os  # unused-ignored\n",
+			want:     []string{"os"},
+		},
+	}
+	for _, tt := range tests {
+		res := New().Analyze(AnalyzeRequest{Filename: tt.filename, Content: tt.content})
+		var got []string
+		for _, issues := range [][]CodeIssue{res.Imports, res.Variables, res.Parameters} {
+			for _, issue := range issues {
+				got = append(got, issue.Symbol)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: reported %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuppressionFix(t *testing.T) {
+	tests := []struct {
+		issue   CodeIssue
+		content string
+		want    string
+	}{
+		{
+			CodeIssue{File: "a.py", Line: 2, Kind: "parameter", Symbol: "x"},
+			"class A:\n    def f(self, x):\n        pass\n",
+			"class A:\n    # unused-ignore-next-line parameter\n    def f(self, x):\n        pass\n",
+		},
+		{
+			CodeIssue{File: "main.go", Line: 3, Kind: "function", Symbol: "f", RuleID: "go/unused-export"},
+			"package p\n\nfunc F() {}\n",
+			"package p\n\n// unused-ignore-next-line export\nfunc F() {}\n",
+		},
+	}
+	for _, tt := range tests {
+		fix := SuppressionFix(tt.issue, tt.content)
+		if fix == nil {
+			t.Errorf("SuppressionFix(%+v) = nil", tt.issue)
+			continue
+		}
+		if got, _ := ApplyTextEdits(tt.content, fix.Edits); got != tt.want {
+			t.Errorf("SuppressionFix(%+v) gives\n%q\nwant\n%q", tt.issue, got, tt.want)
+		}
+	}
+	if fix := SuppressionFix(CodeIssue{File: "notes.txt", Line: 1}, "x\n"); fix != nil {
+		t.Errorf("SuppressionFix of an unsupported file = %+v, want nil", fix)
+	}
+}
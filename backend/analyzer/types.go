@@ -0,0 +1,166 @@
+package analyzer
+
+type Language string
+
+type AnalysisResult struct {
+	Imports    []CodeIssue `json:"imports"`
+	Variables  []CodeIssue `json:"variables"`
+	Parameters []CodeIssue `json:"parameters"`
+	// Exports are exported definitions no other file uses. They are only
+	// reported by workspace analysis when enabled, see exports.go.
+	Exports []CodeIssue `json:"exports"`
+	// Files holds a single finding on the first line when the file is
+	// unreachable from the entry points, see deadfiles.go.
+	Files []CodeIssue `json:"files"`
+}
+
+// CodeIssue is a single finding. Line and Column locate the start of the
+// unused symbol and EndLine and EndColumn the position just after it; lines
+// are 1-based and columns are 1-based and counted in UTF-16 code units, as
+// editors do.
+//
+// Text is meant for display only. Kind is one of the Kind constants, the
+// same for every language, and Symbol the unused name. RuleID identifies the check per language, e.g.
+// "python/unused-import". Fix, when present, removes or rewrites the unused
+// code.
+type CodeIssue struct {
+	ID         string     `json:"id"`
+	Line       int        `json:"line"`
+	Column     int        `json:"column"`
+	EndLine    int        `json:"endLine"`
+	EndColumn  int        `json:"endColumn"`
+	Text       string     `json:"text"`
+	File       string     `json:"file"`
+	Kind       string     `json:"kind"`
+	Symbol     string     `json:"symbol"`
+	RuleID     string     `json:"ruleId"`
+	Severity   Severity   `json:"severity"`
+	Language   Language   `json:"language"`
+	Confidence Confidence `json:"confidence"`
+	Fix        *Fix       `json:"fix,omitempty"`
+}
+
+// Kinds of findings, see CodeIssue.Kind. Parsers report definitions with
+// their own type names, which buildAnalysisResult maps onto these.
+const (
+	KindImport    = "import"
+	KindParameter = "parameter"
+	KindVariable  = "variable"
+	KindConstant  = "constant"
+	KindFunction  = "function"
+	KindMethod    = "method"
+	KindField     = "field"
+	KindClass     = "class"
+	KindInterface = "interface"
+	KindTrait     = "trait"
+	KindModule    = "module"
+	KindType      = "type"
+)
+
+// Span is a range of source text, with positions as in CodeIssue. The end
+// is exclusive.
+type Span struct {
+	Line      int `json:"line"`
+	Column    int `json:"column"`
+	EndLine   int `json:"endLine"`
+	EndColumn int `json:"endColumn"`
+}
+
+// TextEdit replaces the text of Span in File with NewText.
+type TextEdit struct {
+	File string `json:"file"`
+	Span
+	NewText string `json:"newText"`
+}
+
+// Fix is a suggested change resolving a finding. Its edits do not overlap
+// and apply to the file contents the finding was reported for. Every fix
+// is computed as if applied alone, so the fixes of a file may overlap each
+// other, see ApplyTextEdits.
+type Fix struct {
+	Title string     `json:"title"`
+	Edits []TextEdit `json:"edits"`
+}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Confidence tells how certain the analyzer is that a finding is unused:
+// high for type-checked languages, medium for tokenizer-based analysis and
+// low for line-based heuristics.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+type AnalyzeRequest struct {
+	Content  string  `json:"content"`
+	Filename string  `json:"filename"`
+	Language string  `json:"language"`
+	Hash     string  `json:"hash"`
+	Config   *Config `json:"config,omitempty"`
+}
+
+// WorkspaceAnalyzeRequest lists the files analyzed together. Files of no
+// supported language are still visible to languages that need them, such
+// as go.mod for Go import paths. Root is the directory the filenames are
+// in, such as the folder a scan starts from; issue IDs are derived from
+// the filenames relative to it, so that they do not depend on where the
+// scan started. It may be empty for filenames that are relative to the
+// workspace already. When Diff holds a unified diff with filenames relative
+// to Root, only findings declared on lines it adds or modifies are
+// reported, see ParseUnifiedDiff.
+type WorkspaceAnalyzeRequest struct {
+	Files  []AnalyzeFile `json:"files"`
+	Root   string        `json:"root,omitempty"`
+	Config *Config       `json:"config,omitempty"`
+	Diff   string        `json:"diff,omitempty"`
+}
+
+type AnalyzeFile struct {
+	Content  string `json:"content"`
+	Filename string `json:"filename"`
+	Hash     string `json:"hash"`
+}
+
+// Definition is a declared name. Line and Column point at the name itself,
+// not at the keyword introducing it. Exported is set for names other files
+// can import, such as capitalized Go names or Python names listed in
+// __all__. Member is set for names declared in the body of a class, or of
+// its equivalent such as a Go type or a Ruby module.
+type Definition struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Type     string `json:"type"`
+	Exported bool   `json:"exported"`
+	Member   bool   `json:"member,omitempty"`
+}
+
+// Import is a name bound by an import statement. Line, Column, EndLine and
+// EndColumn span the part that binds the name, such as "b as c" in
+// "from a import b as c", while Statement spans the whole statement and is
+// shared by every name the statement binds. Confidence, when set, is the
+// confidence of a finding for the import, such as low for a name that is
+// only guessed from the source.
+type Import struct {
+	Name       string     `json:"name"`
+	File       string     `json:"file"`
+	Line       int        `json:"line"`
+	Column     int        `json:"column"`
+	EndLine    int        `json:"endLine"`
+	EndColumn  int        `json:"endColumn"`
+	Source     string     `json:"source"`
+	Text       string     `json:"text"`
+	Statement  Span       `json:"statement"`
+	Confidence Confidence `json:"confidence,omitempty"`
+}
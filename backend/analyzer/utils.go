@@ -0,0 +1,451 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// issueID derives a deterministic issue ID from the path of the file
+// relative to the workspace root, see relativePath, the kind of finding,
+// the symbol and a fingerprint of the declaring line, so that an issue
+// keeps its ID across scans as long as its file is not renamed and its
+// declaration is unchanged, even when the surrounding code moves it to
+// another line.
+func issueID(file, kind, symbol, fingerprint string) string {
+	sum := sha256.Sum256([]byte(file + "\x00" + kind + "\x00" + symbol + "\x00" + fingerprint))
+	return hex.EncodeToString(sum[:16])
+}
+
+// relativePath returns filename relative to root with "/" as separator,
+// or filename cleaned when it is not below root.
+func relativePath(root, filename string) string {
+	filename = path.Clean(slashPath(filename))
+	root = path.Clean(slashPath(root))
+	switch {
+	case root == ".":
+		return filename
+	case root == "/":
+		return strings.TrimPrefix(filename, "/")
+	}
+	if rel, ok := strings.CutPrefix(filename, root+"/"); ok {
+		return rel
+	}
+	return filename
+}
+
+// lineFingerprint normalizes a source line for issueID: surrounding and
+// repeated whitespace does not change the fingerprint.
+func lineFingerprint(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.Join(strings.Fields(lines[line-1]), " ")
+}
+
+type NamedItem struct {
+	Name string
+	Line int
+}
+
+// isWordBoundary checks if the character at position pos is a word boundary
+func isWordBoundary(s string, pos int) bool {
+	if pos <= 0 || pos >= len(s) {
+		return true
+	}
+	return !unicode.IsLetter(rune(s[pos-1])) && !unicode.IsDigit(rune(s[pos-1])) && s[pos-1] != '_'
+}
+
+// isWordEnd checks if the position pos marks the end of a word
+func isWordEnd(s string, pos int) bool {
+	if pos >= len(s) {
+		return true
+	}
+	return !unicode.IsLetter(rune(s[pos])) && !unicode.IsDigit(rune(s[pos])) && s[pos] != '_'
+}
+
+func FindUsedNames(content string, items []NamedItem) map[string]bool {
+	used := make(map[string]bool)
+	lines := strings.Split(content, "\n")
+
+	for _, item := range items {
+		inBlockComment := false
+		found := false
+		for i, line := range lines {
+			if i+1 == item.Line {
+				continue
+			}
+			line = stripCommentsForUsage(line, &inBlockComment)
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			// Search for the name as a whole word
+			idx := 0
+			for {
+				pos := strings.Index(line[idx:], item.Name)
+				if pos == -1 {
+					break
+				}
+				pos += idx
+
+				// Check if it's a whole word match
+				if isWordBoundary(line, pos) && isWordEnd(line, pos+len(item.Name)) {
+					found = true
+					break
+				}
+				idx = pos + 1
+			}
+
+			if found {
+				break
+			}
+		}
+		if found {
+			used[item.Name] = true
+		}
+	}
+
+	return used
+}
+
+// containsWord checks if a word exists as a whole word in the content
+func containsWord(content, word string) bool {
+	inBlockComment := false
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		line = stripCommentsForUsage(line, &inBlockComment)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if containsWordInLine(line, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWordInLine(line, word string) bool {
+	return indexWordInLine(line, word) >= 0
+}
+
+// indexWordInLine returns the byte offset of the first whole-word
+// occurrence of word in line, or -1.
+func indexWordInLine(line, word string) int {
+	if word == "" {
+		return -1
+	}
+	idx := 0
+	for {
+		pos := strings.Index(line[idx:], word)
+		if pos == -1 {
+			break
+		}
+		pos += idx
+		if isWordBoundary(line, pos) && isWordEnd(line, pos+len(word)) {
+			return pos
+		}
+		idx = pos + 1
+	}
+	return -1
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit editors
+// count columns in. Columns in this package are 1-based UTF-16 offsets
+// within their line.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// utf16Column converts a byte offset within line to a column.
+func utf16Column(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+	return utf16Len(line[:offset]) + 1
+}
+
+func stripCommentsForUsage(line string, inBlockComment *bool) string {
+	if *inBlockComment {
+		if end := strings.Index(line, "*/"); end >= 0 {
+			*inBlockComment = false
+			line = line[end+2:]
+		} else {
+			return ""
+		}
+	}
+
+	if start := strings.Index(line, "/*"); start >= 0 {
+		if end := strings.Index(line[start+2:], "*/"); end >= 0 {
+			line = line[:start] + line[start+2+end+2:]
+		} else {
+			*inBlockComment = true
+			line = line[:start]
+		}
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+		return ""
+	}
+
+	if pos := strings.Index(line, "//"); pos >= 0 {
+		line = line[:pos]
+	}
+	if pos := strings.Index(line, "#"); pos >= 0 {
+		line = line[:pos]
+	}
+
+	return line
+}
+
+func findUsedParameterNames(content string, params []CodeIssue) map[string]bool {
+	var items []NamedItem
+	for _, p := range params {
+		name := p.Symbol
+		if name == "" {
+			continue
+		}
+		items = append(items, NamedItem{Name: name, Line: p.Line})
+	}
+	return FindUsedNames(content, items)
+}
+
+// containsWordInCode is containsWord for content whose comments have already
+// been removed with stripComments.
+func containsWordInCode(code, word string) bool {
+	for _, line := range strings.Split(code, "\n") {
+		if containsWordInLine(line, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordByte reports whether c belongs to a word as isWordBoundary sees
+// it.
+func isWordByte(c byte) bool {
+	return unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c == '_'
+}
+
+// isIdentifierWord reports whether name is a single word, so that
+// containsWordInCode finds it exactly where codeWords does.
+func isIdentifierWord(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isWordByte(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifier reports whether name is an identifier: a letter, "_" or
+// "$" followed by letters, digits, "_" or "$".
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && r != '$' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// nameSet returns the names counts holds, as returned by the FindUsed*Names
+// functions.
+func nameSet(counts map[string]int) map[string]bool {
+	names := make(map[string]bool, len(counts))
+	for name := range counts {
+		names[name] = true
+	}
+	return names
+}
+
+// codeWords returns the set of words of code, split like containsWordInCode
+// splits it.
+func codeWords(code string) map[string]bool {
+	words := make(map[string]bool)
+	start := -1
+	for i := 0; i <= len(code); i++ {
+		if i < len(code) && isWordByte(code[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words[code[start:i]] = true
+			start = -1
+		}
+	}
+	return words
+}
+
+// stripComments removes the comments described by syntax from content while
+// keeping every line in place, so line numbers stay valid.
+func stripComments(content string, syntax CommentSyntax) string {
+	code, _ := splitComments(content, syntax)
+	return strings.Join(code, "\n")
+}
+
+// splitComments separates every line of content into its code and the text
+// of the comments on it, without the comment markers.
+func splitComments(content string, syntax CommentSyntax) (code, comments []string) {
+	lines := strings.Split(content, "\n")
+	code = make([]string, len(lines))
+	comments = make([]string, len(lines))
+	inBlock := false
+	for i, line := range lines {
+		var b, c strings.Builder
+		rest := line
+		for rest != "" {
+			if inBlock {
+				end := strings.Index(rest, syntax.BlockEnd)
+				if end < 0 {
+					c.WriteString(rest)
+					rest = ""
+					break
+				}
+				c.WriteString(rest[:end])
+				c.WriteByte(' ')
+				rest = rest[end+len(syntax.BlockEnd):]
+				inBlock = false
+				continue
+			}
+
+			cut := len(rest)
+			marker := ""
+			isBlock := false
+			if syntax.BlockStart != "" {
+				if pos := strings.Index(rest, syntax.BlockStart); pos >= 0 && pos < cut {
+					cut = pos
+					marker = syntax.BlockStart
+					isBlock = true
+				}
+			}
+			for _, m := range syntax.Line {
+				if pos := strings.Index(rest, m); pos >= 0 && pos < cut {
+					cut = pos
+					marker = m
+					isBlock = false
+				}
+			}
+
+			b.WriteString(rest[:cut])
+			if cut == len(rest) {
+				break
+			}
+			if !isBlock {
+				c.WriteString(rest[cut+len(marker):])
+				break
+			}
+			rest = rest[cut+len(marker):]
+			inBlock = true
+		}
+		code[i] = b.String()
+		comments[i] = c.String()
+	}
+	return code, comments
+}
+
+// blankLines empties every line for which drop returns true, keeping the
+// line count unchanged. drop receives the trimmed line.
+func blankLines(content string, drop func(trimmed string) bool) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if drop(strings.TrimSpace(line)) {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// blankStatements empties every line covered by the statement of one of
+// imports, keeping the line count unchanged.
+func blankStatements(content string, imports []Import) string {
+	lines := strings.Split(content, "\n")
+	for _, imp := range imports {
+		for l := imp.Statement.Line; l >= 1 && l <= imp.Statement.EndLine && l <= len(lines); l++ {
+			lines[l-1] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maskCode replaces the comments of content and the text of its string
+// literals with spaces, keeping every byte offset and line in place. The
+// quotes of strings are kept. Double and single quoted strings end at the
+// end of their line, backquoted and triple-quoted ones may span lines.
+func maskCode(content string, syntax CommentSyntax) string {
+	b := []byte(content)
+	mask := func(from, to int) {
+		for i := from; i < to && i < len(b); i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(content); {
+		rest := content[i:]
+		if syntax.BlockStart != "" && strings.HasPrefix(rest, syntax.BlockStart) {
+			end := strings.Index(rest[len(syntax.BlockStart):], syntax.BlockEnd)
+			if end < 0 {
+				mask(i, len(content))
+				break
+			}
+			end += len(syntax.BlockStart) + len(syntax.BlockEnd)
+			mask(i, i+end)
+			i += end
+			continue
+		}
+		if hasLineCommentPrefix(rest, syntax) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			mask(i, i+end)
+			i += end
+			continue
+		}
+
+		quote := ""
+		switch {
+		case strings.HasPrefix(rest, `"""`), strings.HasPrefix(rest, "'''"):
+			quote = rest[:3]
+		case rest[0] == '"' || rest[0] == '\'' || rest[0] == '`':
+			quote = rest[:1]
+		}
+		if quote == "" {
+			i++
+			continue
+		}
+		j := i + len(quote)
+		for j < len(content) && !strings.HasPrefix(content[j:], quote) {
+			if content[j] == '\\' && quote != "`" {
+				j++
+			} else if content[j] == '\n' && len(quote) == 1 && quote != "`" {
+				break
+			}
+			j++
+		}
+		mask(i+len(quote), j)
+		if j < len(content) && strings.HasPrefix(content[j:], quote) {
+			j += len(quote)
+		}
+		i = j
+	}
+	return string(b)
+}
@@ -0,0 +1,456 @@
+package analyzer
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// workspace is the analyzed state kept between AnalyzeWorkspace and
+// UpdateFiles calls, so that a change costs work proportional to what it
+// affects: only changed files are parsed again, refs makes "is this name
+// used in another file" a lookup, and only the results of changed and
+// affected files are rebuilt, see update.
+type workspace struct {
+	config  *Config
+	root    string // see WorkspaceAnalyzeRequest
+	files   map[string]AnalyzeFile
+	parsed  map[string]ParsedFile
+	words   map[string]map[string]bool // filename to identifiers of its code
+	refs    map[string]map[string]bool // identifier to files whose code has it
+	results map[string]AnalysisResult
+	// resolved holds the keys of WorkspaceResolver languages as for
+	// BuildAnalysisResult's usedNames.
+	resolved map[Language]map[string]bool
+	// exports holds the keys of ExportResolver languages, only resolved
+	// when the Config may report unused exports.
+	exports map[Language]map[string]bool
+	// unreachable holds the files no entry point reaches, only computed
+	// when the Config may report them.
+	unreachable map[string]bool
+}
+
+func newWorkspace(cfg *Config, root string) *workspace {
+	return &workspace{
+		config:   cfg,
+		root:     root,
+		files:    make(map[string]AnalyzeFile),
+		parsed:   make(map[string]ParsedFile),
+		words:    make(map[string]map[string]bool),
+		refs:     make(map[string]map[string]bool),
+		results:  make(map[string]AnalysisResult),
+		resolved: make(map[Language]map[string]bool),
+		exports:  make(map[Language]map[string]bool),
+	}
+}
+
+// workspaceChange collects what changed files and their old versions
+// mention, to find the files whose results may depend on them.
+type workspaceChange struct {
+	// words is the symmetric difference of old and new identifiers.
+	words map[string]bool
+	// mentions holds all old and new identifiers, imports included, per
+	// language.
+	mentions map[Language]map[string]bool
+	// code holds the old and new code of changed files, for names that are
+	// no single identifier.
+	code      []string
+	languages map[Language]bool
+	// unknown is set when a file of no supported language changed, such as
+	// a go.mod a WorkspaceResolver reads.
+	unknown bool
+}
+
+// update applies changed and removed files to ws, which becomes the
+// workspace of a, and rebuilds the results that may have changed,
+// reporting to progress, which may be nil. It returns the names of the
+// files whose results were rebuilt, or ctx.Err() when ctx is done first.
+func (a *MultiLangAnalyzer) update(ctx context.Context, ws *workspace, changed []AnalyzeFile, removed []string, progress ProgressFunc) ([]string, error) {
+	if err := a.parseFiles(ctx, changed, progress); err != nil {
+		return nil, err
+	}
+
+	a.ws = ws
+	ch := &workspaceChange{
+		words:     make(map[string]bool),
+		mentions:  make(map[Language]map[string]bool),
+		languages: make(map[Language]bool),
+	}
+	dirty := make(map[string]bool)
+
+	for _, filename := range removed {
+		if _, ok := ws.files[filename]; ok {
+			a.replaceFile(ws, filename, nil, ch)
+		}
+	}
+	for i := range changed {
+		a.replaceFile(ws, changed[i].Filename, &changed[i], ch)
+		dirty[changed[i].Filename] = true
+	}
+
+	files := ws.sortedFiles()
+	counts := ws.languages()
+	resolvers := make(map[Language]WorkspaceResolver)
+	resolveFiles := 0
+	for lang := range counts {
+		la, _ := LookupLanguage(lang)
+		resolver, ok := la.(WorkspaceResolver)
+		if !ok || (!ch.languages[lang] && !ch.unknown && ws.resolved[lang] != nil) {
+			continue
+		}
+		resolvers[lang] = resolver
+		resolveFiles += counts[lang]
+	}
+	resolved := newProgressCounter(progress, PhaseResolve, resolveFiles)
+	for lang, resolver := range resolvers {
+		if ctx.Err() != nil {
+			a.ws = nil
+			return nil, ctx.Err()
+		}
+		old, oldExports := ws.resolved[lang], ws.exports[lang]
+		ws.resolved[lang] = resolver.ResolveWorkspace(files)
+		if exporter, ok := resolver.(ExportResolver); ok && ws.config.mayEnable(func(rs RuleSet) *bool { return rs.Exports }) {
+			ws.exports[lang] = exporter.ResolveExports(files)
+		}
+		for _, f := range files {
+			if DetectLanguage(f.Filename) != lang || dirty[f.Filename] {
+				continue
+			}
+			for _, name := range ws.declaredNames(f.Filename) {
+				key := name + "@" + f.Filename
+				if old[key] != ws.resolved[lang][key] || oldExports[key] != ws.exports[lang][key] {
+					dirty[f.Filename] = true
+					break
+				}
+			}
+		}
+		resolved.add(counts[lang])
+	}
+
+	if ws.config.mayEnable(func(rs RuleSet) *bool { return rs.DeadFiles }) {
+		unreachable := ws.unreachableFiles(files)
+		for _, f := range files {
+			if unreachable[f.Filename] != ws.unreachable[f.Filename] {
+				dirty[f.Filename] = true
+			}
+		}
+		ws.unreachable = unreachable
+	}
+	for _, f := range files {
+		if !dirty[f.Filename] && ws.affected(f.Filename, ch) {
+			dirty[f.Filename] = true
+		}
+	}
+
+	var rebuild []AnalyzeFile
+	for _, f := range files {
+		if dirty[f.Filename] {
+			rebuild = append(rebuild, f)
+		}
+	}
+	calls := newCallSites(files, ws.parsed, ws.config, ws.root)
+	results := make([]AnalysisResult, len(rebuild))
+	built := newProgressCounter(progress, PhaseBuild, len(rebuild))
+	forEach(len(rebuild), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		results[i] = ws.buildResult(rebuild[i], calls)
+		built.add(1)
+	})
+	if err := ctx.Err(); err != nil {
+		a.ws = nil
+		return nil, err
+	}
+
+	config := configKey(ws.config)
+	rebuilt := make([]string, len(rebuild))
+	for i, f := range rebuild {
+		ws.results[f.Filename] = results[i]
+		a.cache[f.Filename] = CacheEntry{hash: f.Hash, config: config, result: results[i]}
+		rebuilt[i] = f.Filename
+	}
+	return rebuilt, nil
+}
+
+// replaceFile replaces ws's version of filename with file, or removes it
+// when file is nil, recording the change in ch.
+func (a *MultiLangAnalyzer) replaceFile(ws *workspace, filename string, file *AnalyzeFile, ch *workspaceChange) {
+	la := languageFor(filename)
+	if la == nil {
+		ch.unknown = true
+	} else {
+		ch.languages[la.Language()] = true
+	}
+
+	oldWords := ws.words[filename]
+	oldParsed, hadParsed := ws.parsed[filename]
+	for w := range oldWords {
+		if files := ws.refs[w]; files != nil {
+			delete(files, filename)
+			if len(files) == 0 {
+				delete(ws.refs, w)
+			}
+		}
+	}
+	delete(ws.words, filename)
+	delete(ws.parsed, filename)
+
+	var newWords map[string]bool
+	var newParsed ParsedFile
+	if file != nil {
+		ws.files[filename] = *file
+		if la != nil {
+			entry := a.getParsedWorkspaceData(*file, la)
+			newParsed, newWords = entry.ParsedFile, entry.Words
+			ws.parsed[filename] = newParsed
+			ws.words[filename] = newWords
+			for w := range newWords {
+				if ws.refs[w] == nil {
+					ws.refs[w] = make(map[string]bool)
+				}
+				ws.refs[w][filename] = true
+			}
+		}
+	} else {
+		delete(ws.files, filename)
+		delete(ws.results, filename)
+		delete(a.parsedFiles, filename)
+	}
+
+	if la == nil {
+		return
+	}
+	for w := range oldWords {
+		if !newWords[w] {
+			ch.words[w] = true
+		}
+	}
+	for w := range newWords {
+		if !oldWords[w] {
+			ch.words[w] = true
+		}
+	}
+	mentions := ch.mentions[la.Language()]
+	if mentions == nil {
+		mentions = make(map[string]bool)
+		ch.mentions[la.Language()] = mentions
+	}
+	for _, words := range []map[string]bool{oldWords, newWords, importWords(oldParsed), importWords(newParsed)} {
+		for w := range words {
+			mentions[w] = true
+		}
+	}
+	if hadParsed {
+		ch.code = append(ch.code, oldParsed.Code)
+	}
+	if file != nil {
+		ch.code = append(ch.code, newParsed.Code)
+	}
+}
+
+// affected reports whether the result of filename may have changed with
+// ch although the file itself did not: a name it declares appeared in or
+// disappeared from another file, or, when it has unused parameters, a file
+// of its language mentions one of them or of its definitions, which can
+// change the parameter fixes.
+func (ws *workspace) affected(filename string, ch *workspaceChange) bool {
+	la := languageFor(filename)
+	if la == nil {
+		return false
+	}
+	if _, resolved := la.(WorkspaceResolver); !resolved {
+		for _, name := range ws.declaredNames(filename) {
+			if isIdentifierWord(name) {
+				if ch.words[name] {
+					return true
+				}
+				continue
+			}
+			for _, code := range ch.code {
+				if strings.Contains(code, name) {
+					return true
+				}
+			}
+		}
+	}
+
+	res := ws.results[filename]
+	mentions := ch.mentions[la.Language()]
+	if len(res.Parameters) == 0 || len(mentions) == 0 {
+		return false
+	}
+	for _, p := range res.Parameters {
+		if mentions[p.Symbol] {
+			return true
+		}
+	}
+	for _, def := range ws.parsed[filename].Definitions {
+		if mentions[def.Name[strings.LastIndex(def.Name, ".")+1:]] {
+			return true
+		}
+	}
+	return false
+}
+
+// buildResult rebuilds the result of file from the workspace. It only
+// reads the workspace, so results can be built concurrently.
+func (ws *workspace) buildResult(file AnalyzeFile, calls *callSites) AnalysisResult {
+	la := languageFor(file.Filename)
+	if la == nil {
+		return AnalysisResult{}
+	}
+	parsed := ws.parsed[file.Filename]
+
+	usedNames := ws.resolved[la.Language()]
+	if _, resolved := la.(WorkspaceResolver); !resolved {
+		usedNames = make(map[string]bool)
+		for _, name := range ws.declaredNames(file.Filename) {
+			if !fileScoped(la) {
+				usedNames[name+"@"+file.Filename] = ws.usedElsewhere(file.Filename, name)
+			}
+		}
+	}
+
+	result := buildAnalysisResult(la, file, parsed, usedNames, ws.config, ws.root)
+	for i, p := range result.Parameters {
+		result.Parameters[i].Fix = calls.workspaceFix(la, file, p, p.Fix)
+	}
+	imported := usedNames
+	if _, ok := la.(ExportResolver); ok {
+		imported = ws.exports[la.Language()]
+	} else if fileScoped(la) {
+		imported = make(map[string]bool)
+		for _, def := range parsed.Definitions {
+			if def.Exported {
+				imported[def.Name+"@"+file.Filename] = ws.usedElsewhere(file.Filename, def.Name)
+			}
+		}
+	}
+	result.Exports = unusedExports(la, file, parsed, imported, ws.config, ws.root, result.Variables)
+	result.Files = unreachableFileIssues(la, file, ws.config, ws.root, ws.unreachable[file.Filename])
+	return result
+}
+
+// fileScoped reports whether la opts out of cross-file usage, see
+// FileScoped.
+func fileScoped(la LanguageAnalyzer) bool {
+	scoped, ok := la.(FileScoped)
+	return ok && scoped.FileScoped()
+}
+
+// usedElsewhere reports whether name is one of the identifiers of any file
+// but filename, see identifiers. Names that are no single word, such as Go
+// members, are searched for in the code.
+func (ws *workspace) usedElsewhere(filename, name string) bool {
+	if name == "" {
+		return false
+	}
+	if isIdentifierWord(name) {
+		files := ws.refs[name]
+		return len(files) > 1 || (len(files) == 1 && !files[filename])
+	}
+	for other, parsed := range ws.parsed {
+		if other != filename && containsWordInCode(parsed.Code, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredNames returns the names of filename's definitions and imports,
+// whose usage is decided across files.
+func (ws *workspace) declaredNames(filename string) []string {
+	parsed := ws.parsed[filename]
+	var names []string
+	for _, def := range parsed.Definitions {
+		if def.Name != "" {
+			names = append(names, def.Name)
+		}
+	}
+	for _, imp := range parsed.Imports {
+		names = append(names, importNames(imp)...)
+	}
+	return names
+}
+
+// languages returns the number of files of each language.
+func (ws *workspace) languages() map[Language]int {
+	langs := make(map[Language]int)
+	for filename := range ws.files {
+		if la := languageFor(filename); la != nil {
+			langs[la.Language()]++
+		}
+	}
+	return langs
+}
+
+// sortedFiles returns the files of the workspace ordered by name, so that
+// results do not depend on map order.
+func (ws *workspace) sortedFiles() []AnalyzeFile {
+	files := make([]AnalyzeFile, 0, len(ws.files))
+	for _, f := range ws.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+	return files
+}
+
+// identifiers returns the names code, the Code of a file of language la,
+// refers to: those la's tokenizer finds, or every word of code.
+func identifiers(la LanguageAnalyzer, code string) map[string]bool {
+	if tokenizer, ok := la.(IdentifierTokenizer); ok {
+		return tokenizer.Identifiers(code)
+	}
+	return codeWords(code)
+}
+
+// importWords returns the identifiers of the import statements of parsed,
+// which its Code leaves out.
+func importWords(parsed ParsedFile) map[string]bool {
+	var b strings.Builder
+	for _, imp := range parsed.Imports {
+		b.WriteString(imp.Text)
+		b.WriteByte('\n')
+	}
+	return codeWords(b.String())
+}
+
+// forEach calls fn for every index below n on a pool of up to GOMAXPROCS
+// workers and waits for all calls to return. WebAssembly runs on a single
+// thread, so there fn is simply called in order.
+func forEach(n int, fn func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if runtime.GOOS == "js" {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
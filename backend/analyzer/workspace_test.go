@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestUpdateFilesMatchesFreshScan applies a sequence of edits, removals
+// and additions with UpdateFiles and checks after every step that the
+// results it has returned so far equal those of a fresh AnalyzeWorkspace
+// of the same files.
+func TestUpdateFilesMatchesFreshScan(t *testing.T) {
+	initial := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.22\n",
+		"lib.py":       "import os\n\ndef used():\n    return os.getcwd()\n\ndef later():\n    pass\n",
+		"app.py":       "from lib import used\n\nused()\n",
+		"util/util.go": "package util\n\nfunc Helper() int {\n\treturn 1\n}\n\nfunc Other(x int) int {\n\treturn 2\n}\n",
+		"main.go":      "package main\n\nimport \"example.com/app/util\"\n\nfunc main() {\n\tutil.Helper()\n}\n",
+		"models.rb":    "class Model\n  def save(force)\n    true\n  end\nend\n",
+		"Service.php":  "<?php\nnamespace App;\n\nclass Service {\n    public function run($a) {\n        return 1;\n    }\n}\n",
+	}
+	steps := []struct {
+		name    string
+		changed map[string]string
+		removed []string
+	}{
+		{
+			name:    "use a second definition",
+			changed: map[string]string{"app.py": "from lib import used, later\n\nused()\nlater()\n"},
+		},
+		{
+			name:    "remove the importing file",
+			removed: []string{"app.py"},
+		},
+		{
+			name: "add importers back",
+			changed: map[string]string{
+				"app.py":  "import lib\n\nlib.used()\n",
+				"cli.py":  "from lib import later\n\nlater()\n",
+				"jobs.rb": "require_relative 'models'\n\nModel.new.save(true)\n",
+			},
+		},
+		{
+			name: "call another function across packages",
+			changed: map[string]string{
+				"main.go": "package main\n\nimport \"example.com/app/util\"\n\nfunc main() {\n\tutil.Helper()\n\tutil.Other(1)\n}\n",
+			},
+		},
+		{
+			name:    "remove a package",
+			removed: []string{"util/util.go"},
+		},
+		{
+			name: "add a component and a PHP caller",
+			changed: map[string]string{
+				"web/Comp.svelte": "<script>\n  import { onMount } from 'svelte';\n  export let title = '';\n  let unused = 1;\n</script>\n\n<h1>{title}</h1>\n",
+				"index.php":       "<?php\nuse App\\Service;\n\n(new Service())->run(1);\n",
+			},
+		},
+		{
+			name:    "edit and remove in one update",
+			changed: map[string]string{"lib.py": "def used():\n    pass\n"},
+			removed: []string{"cli.py", "models.rb"},
+		},
+		{
+			name:    "re-add a removed package",
+			changed: map[string]string{"util/util.go": initial["util/util.go"]},
+		},
+	}
+
+	for _, config := range []string{`{}`, `{"exports": true, "entryPoints": ["main.go", "app.py"], "deadFiles": true}`} {
+		cfg, err := ParseConfig([]byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := make(map[string]string, len(initial))
+		for name, content := range initial {
+			files[name] = content
+		}
+
+		a := New()
+		current := a.AnalyzeWorkspace(workspaceRequest(files, cfg)).Results
+		for _, step := range steps {
+			var changed []AnalyzeFile
+			for name, content := range step.changed {
+				files[name] = content
+				changed = append(changed, AnalyzeFile{Filename: name, Content: content})
+			}
+			for _, name := range step.removed {
+				delete(files, name)
+				delete(current, name)
+			}
+			for name, res := range a.UpdateFiles(changed, step.removed).Results {
+				current[name] = res
+			}
+
+			fresh := New().AnalyzeWorkspace(workspaceRequest(files, cfg)).Results
+			for name := range fresh {
+				if got, want := resultJSON(t, current[name]), resultJSON(t, fresh[name]); got != want {
+					t.Errorf("config %s, %s: %s\nincremental: %s\nfresh:       %s", config, step.name, name, got, want)
+				}
+			}
+			for name := range current {
+				if _, ok := fresh[name]; !ok {
+					t.Errorf("config %s, %s: stale result for %s", config, step.name, name)
+				}
+			}
+		}
+	}
+}
+
+// workspaceRequest builds a request for files in a stable order.
+func workspaceRequest(files map[string]string, cfg *Config) WorkspaceAnalyzeRequest {
+	req := WorkspaceAnalyzeRequest{Config: cfg}
+	for name, content := range files {
+		req.Files = append(req.Files, AnalyzeFile{Filename: name, Content: content})
+	}
+	sort.Slice(req.Files, func(i, j int) bool { return req.Files[i].Filename < req.Files[j].Filename })
+	return req
+}
+
+func resultJSON(t *testing.T, res AnalysisResult) string {
+	t.Helper()
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestIdentifierIndex(t *testing.T) {
+	files := map[string]string{
+		"lib.py":    "def in_code():\n    pass\n\ndef in_string():\n    pass\n\ndef in_template():\n    pass\n",
+		"app.py":    "in_code()\nprint('in_string')\n",
+		"View.vue":  "<template>\n  <p>{{ in_template() }}</p>\n</template>\n",
+		"tasks.rb":  "def run\nend\n\ndef in_symbol\nend\n",
+		"worker.rb": "send(:in_symbol)\nputs 'run'\n",
+	}
+	res := New().AnalyzeWorkspace(workspaceRequest(files, nil))
+	var got []string
+	for _, filename := range []string{"lib.py", "tasks.rb"} {
+		for _, issue := range res.Results[filename].Variables {
+			got = append(got, issue.Symbol)
+		}
+	}
+	// Strings do not count as usage in languages with a tokenizer; words of
+	// component markup do.
+	if want := []string{"in_string", "run"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unused %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		la   LanguageAnalyzer
+		code string
+		want []string
+	}{
+		{languageFor("a.py"), "x = y + \"z\"\nif w: pass\n", []string{"w", "x", "y"}},
+		{languageFor("a.rb"), "def run(a)\n  a.call(:sym, 'str')\nend\n", []string{"a", "call", "run", "sym"}},
+		{languageFor("a.php"), "$x = foo('bar');\n", []string{"foo", "x"}},
+		{languageFor("a.go"), "package p\n\nvar s = \"str\" + t // u\n", []string{"p", "s", "t"}},
+	}
+	for _, tt := range tests {
+		var names []string
+		for name := range identifiers(tt.la, tt.code) {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: identifiers %v, want %v", tt.la.Language(), names, tt.want)
+		}
+	}
+}
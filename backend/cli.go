@@ -0,0 +1,588 @@
+//go:build !js
+
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/selcuksarikoz/unused-code-analyzer/backend/analyzer"
+)
+
+const (
+	exitOK     = 0
+	exitIssues = 1
+	exitError  = 2
+)
+
+// maxFixPasses bounds how often -fix re-analyzes to apply fixes that
+// overlapped others in the previous pass.
+const maxFixPasses = 10
+
+var defaultExcludeFolders = []string{"node_modules", ".next", "dist", "build", "out", ".git", "vendor"}
+
+type cliOptions struct {
+	root           string
+	exclude        []string
+	format         string
+	config         string
+	fix            bool
+	baseline       string
+	updateBaseline bool
+	diff           string
+	diffBase       string
+	diffHead       string
+	cache          string
+	explain        string
+}
+
+type cliIssue struct {
+	Category string `json:"category"`
+	analyzer.CodeIssue
+}
+
+func main() {
+	os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("unused-code-analyzer", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: unused-code-analyzer [flags] [path]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Scans path (default \".\") for unused imports, variables and parameters.")
+		fmt.Fprintln(stderr, "Exits with status 1 when issues are found and 2 on errors.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	exclude := flags.String("exclude", strings.Join(defaultExcludeFolders, ","), "comma-separated folder names to skip")
+	format := flags.String("format", "text", "output format: text, json or sarif")
+	config := flags.String("config", "", "path to a config file (default: "+analyzer.ConfigFileName+" in path or a parent)")
+	lsp := flags.Bool("lsp", false, "run as a Language Server Protocol server on stdin and stdout")
+	fix := flags.Bool("fix", false, "apply the suggested fixes in place and report what is left")
+	baseline := flags.String("baseline", "", "report only findings missing from this baseline file")
+	diff := flags.String("diff", "", "report only findings on lines added by this unified diff file (- for stdin)")
+	diffBase := flags.String("diff-base", "", "report only findings on lines changed since this git revision")
+	diffHead := flags.String("diff-head", "", "git revision compared with -diff-base (default: the working tree)")
+	cache := flags.String("cache", "", "keep parses in this file to speed up the next run")
+	explain := flags.String("explain", "", "explain why `file:symbol` is reported as unused or not, listing the references found")
+	updateBaseline := flags.Bool("update-baseline", false, "write all findings to the baseline file (default: "+analyzer.BaselineFileName+" in path) and exit")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitError
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitError
+	}
+	if *diff != "" && *diffBase != "" {
+		fmt.Fprintln(stderr, "-diff and -diff-base cannot be combined")
+		return exitError
+	}
+	if *diffHead != "" && *diffBase == "" {
+		fmt.Fprintln(stderr, "-diff-head requires -diff-base")
+		return exitError
+	}
+	if *fix && (*diff != "" || *diffBase != "") {
+		fmt.Fprintln(stderr, "-fix cannot be combined with -diff or -diff-base")
+		return exitError
+	}
+	if *explain != "" && (*fix || *updateBaseline) {
+		fmt.Fprintln(stderr, "-explain cannot be combined with -fix or -update-baseline")
+		return exitError
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return exitError
+	}
+
+	opts := cliOptions{
+		root:           ".",
+		exclude:        splitList(*exclude),
+		format:         *format,
+		config:         *config,
+		fix:            *fix,
+		baseline:       *baseline,
+		updateBaseline: *updateBaseline,
+		diff:           *diff,
+		diffBase:       *diffBase,
+		diffHead:       *diffHead,
+		cache:          *cache,
+		explain:        *explain,
+	}
+	if flags.NArg() == 1 {
+		opts.root = flags.Arg(0)
+	}
+	if *lsp {
+		return runLSP(opts, os.Stdin, stdout, stderr)
+	}
+
+	files, err := collectFiles(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	diffText, err := readDiff(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	a := analyzer.New()
+	loadParseCache(a, opts.cache, stderr)
+	req := analyzer.WorkspaceAnalyzeRequest{Files: files, Root: filepath.ToSlash(opts.root), Config: cfg}
+	result := a.AnalyzeWorkspace(req)
+	if opts.fix {
+		result, err = applyFixes(a, req, result, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	saveParseCache(a, opts.cache, stderr)
+	if opts.explain != "" {
+		return explainSymbol(a, opts, stdout, stderr)
+	}
+	if opts.updateBaseline {
+		if err := writeBaseline(opts, result, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+	// The baseline sees the whole workspace, or every recorded finding
+	// outside the diff would count as fixed.
+	if opts.baseline != "" {
+		result, err = filterBaseline(opts.baseline, result, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	// Filtered here rather than through the request, as an empty diff
+	// still means that nothing changed.
+	if opts.diff != "" || opts.diffBase != "" {
+		result = analyzer.ParseUnifiedDiff(diffText).Filter(result, filepath.ToSlash(opts.root))
+	}
+	issues := flattenResults(result)
+
+	switch opts.format {
+	case "json", "sarif":
+		var out any = issues
+		if opts.format == "sarif" {
+			log := analyzer.NewSARIFLog(result, filepath.ToSlash(opts.root))
+			if dir, err := filepath.Abs(opts.root); err == nil {
+				log.SetSourceRoot(dir)
+			}
+			out = log
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	default:
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s:%d:%d: unused %s: %s\n", issue.File, issue.Line, issue.Column, issue.Category, issue.Text)
+		}
+		if len(issues) > 0 {
+			fmt.Fprintf(stdout, "\n%d issue(s) in %d file(s) scanned\n", len(issues), len(files))
+		}
+	}
+
+	if len(issues) > 0 {
+		return exitIssues
+	}
+	return exitOK
+}
+
+// analyzable reports whether collectFiles reads the file at path: one of a
+// supported language, or go.mod, which is passed along so Go imports
+// resolve to workspace packages.
+func analyzable(path string) bool {
+	return analyzer.DetectLanguage(path) != analyzer.LangUnknown || filepath.Base(path) == "go.mod"
+}
+
+func collectFiles(opts cliOptions) ([]analyzer.AnalyzeFile, error) {
+	excluded := make(map[string]bool, len(opts.exclude))
+	for _, name := range opts.exclude {
+		excluded[name] = true
+	}
+
+	var files []analyzer.AnalyzeFile
+	err := filepath.WalkDir(opts.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != opts.root && excluded[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !analyzable(path) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := md5.Sum(content)
+		files = append(files, analyzer.AnalyzeFile{
+			Content:  string(content),
+			Filename: filepath.ToSlash(path),
+			Hash:     hex.EncodeToString(sum[:]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// applyFixes writes the fixes of result to disk, re-analyzing after every
+// pass until no fix is left, and returns the final result. The files of
+// req are updated with the new contents.
+func applyFixes(a *analyzer.MultiLangAnalyzer, req analyzer.WorkspaceAnalyzeRequest, result analyzer.WorkspaceAnalysisResult, stderr io.Writer) (analyzer.WorkspaceAnalysisResult, error) {
+	files := req.Files
+	index := make(map[string]int, len(files))
+	for i, f := range files {
+		index[f.Filename] = i
+	}
+
+	for pass := 0; pass < maxFixPasses; pass++ {
+		edits := make(map[string][]analyzer.TextEdit)
+		for _, issue := range flattenResults(result) {
+			if issue.Fix == nil || conflicts(edits, issue.Fix.Edits) {
+				continue
+			}
+			for _, edit := range issue.Fix.Edits {
+				edits[edit.File] = append(edits[edit.File], edit)
+			}
+		}
+		if len(edits) == 0 {
+			break
+		}
+
+		changed := false
+		for filename, fileEdits := range edits {
+			i, ok := index[filename]
+			if !ok {
+				continue
+			}
+			content, _ := analyzer.ApplyTextEdits(files[i].Content, fileEdits)
+			if content == files[i].Content {
+				continue
+			}
+			if err := os.WriteFile(filepath.FromSlash(filename), []byte(content), 0o644); err != nil {
+				return result, err
+			}
+			sum := md5.Sum([]byte(content))
+			files[i].Content = content
+			files[i].Hash = hex.EncodeToString(sum[:])
+			changed = true
+			fmt.Fprintf(stderr, "fixed %s\n", filename)
+		}
+		if !changed {
+			break
+		}
+		result = a.AnalyzeWorkspace(req)
+	}
+	return result, nil
+}
+
+// conflicts reports whether any of edits overlaps one of accepted, keyed by
+// file, without being identical to it. A fix spanning several edits, like
+// the removal of a parameter along with its arguments, is only applied as a
+// whole, so a conflicting fix waits for the next pass.
+func conflicts(accepted map[string][]analyzer.TextEdit, edits []analyzer.TextEdit) bool {
+	for _, e := range edits {
+		for _, a := range accepted[e.File] {
+			if a == e {
+				continue
+			}
+			if before(e.Line, e.Column, a.EndLine, a.EndColumn) && before(a.Line, a.Column, e.EndLine, e.EndColumn) {
+				return true
+			}
+			if a.Span == e.Span {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func before(line, column, otherLine, otherColumn int) bool {
+	return line < otherLine || (line == otherLine && column < otherColumn)
+}
+
+// readDiff returns the diff given with -diff, or the one git computes for
+// -diff-base and -diff-head with paths relative to the scanned directory.
+func readDiff(opts cliOptions) (string, error) {
+	switch {
+	case opts.diff == "-":
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	case opts.diff != "":
+		data, err := os.ReadFile(opts.diff)
+		return string(data), err
+	case opts.diffBase == "":
+		return "", nil
+	}
+
+	dir := opts.root
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	args := []string{"-C", dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative", opts.diffBase}
+	if opts.diffHead != "" {
+		args = append(args, opts.diffHead)
+	}
+	args = append(args, "--")
+	var errOut strings.Builder
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &errOut
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff: %v: %s", err, strings.TrimSpace(errOut.String()))
+	}
+	return string(out), nil
+}
+
+// baselinePath returns the file given with -baseline or else
+// analyzer.BaselineFileName in the scanned directory.
+func baselinePath(opts cliOptions) string {
+	if opts.baseline != "" {
+		return opts.baseline
+	}
+	dir := opts.root
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Join(dir, analyzer.BaselineFileName)
+}
+
+func writeBaseline(opts cliOptions, result analyzer.WorkspaceAnalysisResult, stderr io.Writer) error {
+	b := analyzer.NewBaseline(result, filepath.ToSlash(opts.root))
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := baselinePath(opts)
+	if err := os.WriteFile(name, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "wrote %d issue(s) to %s\n", len(b.Issues), name)
+	return nil
+}
+
+// filterBaseline drops the findings recorded in the baseline file name from
+// result and lists the recorded findings that have been fixed on stderr.
+func filterBaseline(name string, result analyzer.WorkspaceAnalysisResult, stderr io.Writer) (analyzer.WorkspaceAnalysisResult, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return result, err
+	}
+	b, err := analyzer.ParseBaseline(data)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", name, err)
+	}
+	result, fixed := b.Filter(result)
+	for _, issue := range fixed {
+		fmt.Fprintf(stderr, "%s: fixed since baseline: %s %s\n", issue.File, issue.RuleID, issue.Symbol)
+	}
+	if len(fixed) > 0 {
+		fmt.Fprintf(stderr, "%d baseline issue(s) fixed, run with -update-baseline to remove them\n", len(fixed))
+	}
+	return result, nil
+}
+
+// explainSymbol prints the explanation of the -explain argument, a
+// filename as reported by the scan and a symbol separated by a colon.
+func explainSymbol(a *analyzer.MultiLangAnalyzer, opts cliOptions, stdout, stderr io.Writer) int {
+	i := strings.LastIndex(opts.explain, ":")
+	if i < 0 {
+		fmt.Fprintln(stderr, "-explain expects file:symbol")
+		return exitError
+	}
+	filename := filepath.ToSlash(filepath.Clean(opts.explain[:i]))
+	ex := a.Explain(analyzer.ExplainRequest{Filename: filename, Symbol: opts.explain[i+1:]})
+	if ex.Kind == "" {
+		fmt.Fprintf(stderr, "%s declares no %q\n", filename, ex.Symbol)
+		return exitError
+	}
+
+	if opts.format != "text" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(ex); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+	status := "is used"
+	switch {
+	case ex.Reported && ex.Used:
+		status = "is used, but reported as unused export"
+	case ex.Reported:
+		status = "is unused and reported"
+	case !ex.Used && ex.Exemption != "":
+		status = "is unused but not reported: " + ex.Exemption
+	case !ex.Used:
+		status = "is unused but not reported"
+	}
+	fmt.Fprintf(stdout, "%s: %s %s %s\n", ex.File, ex.Kind, ex.Symbol, status)
+	for _, ref := range ex.References {
+		if ref.Line == 0 {
+			fmt.Fprintf(stdout, "  %s: %s: %s\n", ref.File, ref.Rule, ref.Text)
+			continue
+		}
+		fmt.Fprintf(stdout, "  %s:%d:%d: %s: %s\n", ref.File, ref.Line, ref.Column, ref.Rule, ref.Text)
+	}
+	return exitOK
+}
+
+// loadParseCache fills a's parse cache from the file name, if any. The
+// cache only saves time, so a missing or unreadable file is no error.
+func loadParseCache(a *analyzer.MultiLangAnalyzer, name string, stderr io.Writer) {
+	if name == "" {
+		return
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stderr, "ignoring parse cache: %v\n", err)
+		}
+		return
+	}
+	cache, err := analyzer.DecodeParseCache(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "ignoring parse cache %s: %v\n", name, err)
+		return
+	}
+	a.ImportParseCache(cache)
+}
+
+// saveParseCache writes a's parse cache to the file name, if any. The file
+// is replaced in one step, so that an interrupted run leaves the old cache.
+func saveParseCache(a *analyzer.MultiLangAnalyzer, name string, stderr io.Writer) {
+	if name == "" {
+		return
+	}
+	data, err := json.Marshal(a.ExportParseCache(analyzer.DefaultParseCacheLimits))
+	if err == nil {
+		tmp := name + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, name)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "could not save parse cache: %v\n", err)
+	}
+}
+
+// loadConfig reads the file given with -config or else the nearest
+// analyzer.ConfigFileName in the scanned directory or its parents. No config
+// file is not an error.
+func loadConfig(opts cliOptions) (*analyzer.Config, error) {
+	name := opts.config
+	if name == "" {
+		dir, err := filepath.Abs(opts.root)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		for {
+			candidate := filepath.Join(dir, analyzer.ConfigFileName)
+			if _, err := os.Stat(candidate); err == nil {
+				name = candidate
+				break
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return nil, nil
+			}
+			dir = parent
+		}
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := analyzer.ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+func flattenResults(result analyzer.WorkspaceAnalysisResult) []cliIssue {
+	var issues []cliIssue
+	for _, res := range result.Results {
+		for _, issue := range res.Imports {
+			issues = append(issues, cliIssue{Category: "import", CodeIssue: issue})
+		}
+		for _, issue := range res.Variables {
+			issues = append(issues, cliIssue{Category: "variable", CodeIssue: issue})
+		}
+		for _, issue := range res.Parameters {
+			issues = append(issues, cliIssue{Category: "parameter", CodeIssue: issue})
+		}
+		for _, issue := range res.Exports {
+			issues = append(issues, cliIssue{Category: "export", CodeIssue: issue})
+		}
+		for _, issue := range res.Files {
+			issues = append(issues, cliIssue{Category: "file", CodeIssue: issue})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
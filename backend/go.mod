@@ -0,0 +1,3 @@
+module github.com/selcuksarikoz/unused-code-analyzer/backend
+
+go 1.22
@@ -0,0 +1,644 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/selcuksarikoz/unused-code-analyzer/backend/analyzer"
+)
+
+const (
+	// lspAnalyzeCommand rescans the whole root, e.g. after a branch switch.
+	lspAnalyzeCommand = "unused-code-analyzer.analyzeWorkspace"
+
+	// lspDelay is the pause after a change before the workspace is
+	// re-analyzed, so that typing does not trigger an analysis per key.
+	lspDelay = 300 * time.Millisecond
+
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+)
+
+// lspMessage is a JSON-RPC request, response or notification.
+type lspMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+	Tags     []int    `json:"tags,omitempty"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspWorkspaceEdit struct {
+	Changes map[string][]lspTextEdit `json:"changes"`
+}
+
+type lspCodeAction struct {
+	Title       string           `json:"title"`
+	Kind        string           `json:"kind"`
+	Diagnostics []lspDiagnostic  `json:"diagnostics,omitempty"`
+	IsPreferred bool             `json:"isPreferred,omitempty"`
+	Edit        lspWorkspaceEdit `json:"edit"`
+}
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspInitializeParams struct {
+	RootURI          string `json:"rootUri"`
+	RootPath         string `json:"rootPath"`
+	WorkspaceFolders []struct {
+		URI string `json:"uri"`
+	} `json:"workspaceFolders"`
+}
+
+type lspDocumentParams struct {
+	TextDocument   lspTextDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Text *string `json:"text"`
+}
+
+type lspCodeActionParams struct {
+	TextDocument lspTextDocument `json:"textDocument"`
+	Range        lspRange        `json:"range"`
+}
+
+type lspFileEvents struct {
+	Changes []struct {
+		URI  string `json:"uri"`
+		Type int    `json:"type"`
+	} `json:"changes"`
+}
+
+type lspCommandParams struct {
+	Command string `json:"command"`
+}
+
+// lspServer holds the workspace as the editor sees it. Messages are handled
+// one at a time; analyses scheduled by changes run on a timer and share mu.
+type lspServer struct {
+	opts     cliOptions
+	in       *bufio.Reader
+	out      io.Writer
+	stderr   io.Writer
+	outMu    sync.Mutex
+	analyzer *analyzer.MultiLangAnalyzer
+
+	mu        sync.Mutex
+	cfg       *analyzer.Config
+	files     map[string]analyzer.AnalyzeFile
+	open      map[string]bool
+	results   map[string]analyzer.AnalysisResult
+	published map[string]bool
+	// changed lists the files changed or removed since the last analysis,
+	// which rescan replaces by a full analysis.
+	changed  map[string]bool
+	rescan   bool
+	timer    *time.Timer
+	shutdown bool
+}
+
+// runLSP serves the Language Server Protocol on in and out until the client
+// sends exit. opts supplies -exclude, -config and -cache; the root comes
+// from the client and is scanned as the command line scans a directory,
+// with open documents replacing the files on disk. Changes are passed to
+// UpdateFiles after lspDelay and the diagnostics of the files it returns
+// are published.
+func runLSP(opts cliOptions, in io.Reader, out, stderr io.Writer) int {
+	s := &lspServer{
+		opts:      opts,
+		in:        bufio.NewReader(in),
+		out:       out,
+		stderr:    stderr,
+		analyzer:  analyzer.New(),
+		files:     make(map[string]analyzer.AnalyzeFile),
+		open:      make(map[string]bool),
+		results:   make(map[string]analyzer.AnalysisResult),
+		published: make(map[string]bool),
+		changed:   make(map[string]bool),
+	}
+	loadParseCache(s.analyzer, opts.cache, stderr)
+	for {
+		msg, err := s.read()
+		if err != nil {
+			return exitError
+		}
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return exitOK
+			}
+			return exitError
+		}
+		s.handle(msg)
+	}
+}
+
+// read reads one message framed by a Content-Length header.
+func (s *lspServer) read() (*lspMessage, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	var msg lspMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *lspServer) write(msg lspMessage) {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *lspServer) reply(id json.RawMessage, result any) {
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	s.write(lspMessage{ID: id, Result: result})
+}
+
+func (s *lspServer) replyError(id json.RawMessage, code int, message string) {
+	s.write(lspMessage{ID: id, Error: &lspError{Code: code, Message: message}})
+}
+
+func (s *lspServer) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	s.write(lspMessage{Method: method, Params: data})
+}
+
+func (s *lspServer) handle(msg *lspMessage) {
+	decode := func(v any) bool {
+		if err := json.Unmarshal(msg.Params, v); err != nil {
+			if msg.ID != nil {
+				s.replyError(msg.ID, lspInvalidParams, err.Error())
+			}
+			return false
+		}
+		return true
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params lspInitializeParams
+		if decode(&params) {
+			s.initialize(params)
+			s.reply(msg.ID, map[string]any{
+				"capabilities": map[string]any{
+					"textDocumentSync": map[string]any{
+						"openClose": true,
+						"change":    1, // full documents
+						"save":      map[string]any{"includeText": true},
+					},
+					"codeActionProvider":     map[string]any{"codeActionKinds": []string{"quickfix"}},
+					"executeCommandProvider": map[string]any{"commands": []string{lspAnalyzeCommand}},
+				},
+				"serverInfo": map[string]any{"name": analyzer.SARIFToolName},
+			})
+		}
+	case "initialized":
+		s.analyzeNow()
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.mu.Unlock()
+		saveParseCache(s.analyzer, s.opts.cache, s.stderr)
+		s.reply(msg.ID, nil)
+	case "textDocument/didOpen", "textDocument/didChange", "textDocument/didSave", "textDocument/didClose":
+		var params lspDocumentParams
+		if decode(&params) {
+			s.updateDocument(msg.Method, params)
+		}
+	case "workspace/didChangeWatchedFiles":
+		var params lspFileEvents
+		if decode(&params) {
+			s.mu.Lock()
+			for _, change := range params.Changes {
+				s.reloadFile(uriToPath(change.URI))
+			}
+			s.mu.Unlock()
+			s.schedule(lspDelay)
+		}
+	case "workspace/executeCommand":
+		var params lspCommandParams
+		if !decode(&params) {
+			return
+		}
+		if params.Command != lspAnalyzeCommand {
+			s.replyError(msg.ID, lspInvalidParams, "unknown command "+params.Command)
+			return
+		}
+		s.mu.Lock()
+		s.scan()
+		s.mu.Unlock()
+		s.analyzeNow()
+		s.reply(msg.ID, nil)
+	case "textDocument/codeAction":
+		var params lspCodeActionParams
+		if decode(&params) {
+			s.reply(msg.ID, s.codeActions(params))
+		}
+	default:
+		if msg.ID != nil && msg.Method != "" {
+			s.replyError(msg.ID, lspMethodNotFound, "method not supported: "+msg.Method)
+		}
+	}
+}
+
+func (s *lspServer) initialize(params lspInitializeParams) {
+	root := params.RootPath
+	switch {
+	case len(params.WorkspaceFolders) > 0:
+		root = uriToPath(params.WorkspaceFolders[0].URI)
+	case params.RootURI != "":
+		root = uriToPath(params.RootURI)
+	}
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.root = filepath.FromSlash(root)
+	s.scan()
+}
+
+// scan reads the configuration and the files below the root again. Open
+// documents keep the editor's content.
+func (s *lspServer) scan() {
+	cfg, err := loadConfig(s.opts)
+	if err != nil {
+		s.logError(err)
+	}
+	s.cfg = cfg
+
+	files, err := collectFiles(s.opts)
+	if err != nil {
+		s.logError(err)
+	}
+	scanned := make(map[string]analyzer.AnalyzeFile, len(files))
+	for _, f := range files {
+		scanned[f.Filename] = f
+	}
+	for name := range s.open {
+		scanned[name] = s.files[name]
+	}
+	s.files = scanned
+	s.rescan = true
+}
+
+// reloadFile reads filename from disk unless it is open, or forgets it when
+// it no longer exists or scan would not read it.
+func (s *lspServer) reloadFile(filename string) {
+	if filename == "" || s.open[filename] {
+		return
+	}
+	content, err := os.ReadFile(filepath.FromSlash(filename))
+	if err != nil || !s.scanned(filename) {
+		delete(s.files, filename)
+	} else {
+		s.files[filename] = newAnalyzeFile(filename, string(content))
+	}
+	s.changed[filename] = true
+}
+
+// scanned reports whether scan reads filename: an analyzable file below
+// the root and outside of the excluded folders.
+func (s *lspServer) scanned(filename string) bool {
+	rel, err := filepath.Rel(s.opts.root, filepath.FromSlash(filename))
+	if err != nil || !analyzable(filename) {
+		return false
+	}
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	for _, dir := range dirs {
+		if dir == ".." || slices.Contains(s.opts.exclude, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *lspServer) updateDocument(method string, params lspDocumentParams) {
+	filename := uriToPath(params.TextDocument.URI)
+	if filename == "" {
+		return
+	}
+
+	s.mu.Lock()
+	delay := time.Duration(0)
+	switch method {
+	case "textDocument/didOpen":
+		s.open[filename] = true
+		s.files[filename] = newAnalyzeFile(filename, params.TextDocument.Text)
+	case "textDocument/didChange":
+		if n := len(params.ContentChanges); n > 0 {
+			s.files[filename] = newAnalyzeFile(filename, params.ContentChanges[n-1].Text)
+		}
+		delay = lspDelay
+	case "textDocument/didSave":
+		if params.Text != nil {
+			s.files[filename] = newAnalyzeFile(filename, *params.Text)
+		}
+	case "textDocument/didClose":
+		delete(s.open, filename)
+		s.reloadFile(filename)
+	}
+	s.changed[filename] = true
+	s.mu.Unlock()
+
+	s.schedule(delay)
+}
+
+// schedule analyzes the workspace after delay, postponing an analysis
+// already scheduled.
+func (s *lspServer) schedule(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(delay, s.analyzeNow)
+}
+
+// analyzeNow analyzes the workspace if anything changed since the last
+// analysis and publishes the diagnostics that changed.
+func (s *lspServer) analyzeNow() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.analyzeLocked()
+}
+
+// analyzeLocked runs the analysis with mu held. After a scan the whole
+// workspace is analyzed; otherwise only the changed files are passed on to
+// UpdateFiles, which returns the results that may have changed.
+func (s *lspServer) analyzeLocked() {
+	if s.shutdown || (!s.rescan && len(s.changed) == 0) {
+		return
+	}
+
+	var result analyzer.WorkspaceAnalysisResult
+	if s.rescan {
+		files := make([]analyzer.AnalyzeFile, 0, len(s.files))
+		for _, f := range s.files {
+			files = append(files, f)
+		}
+		result = s.analyzer.AnalyzeWorkspace(analyzer.WorkspaceAnalyzeRequest{Files: files, Root: filepath.ToSlash(s.opts.root), Config: s.cfg})
+		s.results = result.Results
+		for filename := range s.published {
+			if _, ok := result.Results[filename]; !ok {
+				delete(s.published, filename)
+				s.publish(filename, nil)
+			}
+		}
+	} else {
+		var changed []analyzer.AnalyzeFile
+		var removed []string
+		for filename := range s.changed {
+			if f, ok := s.files[filename]; ok {
+				changed = append(changed, f)
+			} else {
+				removed = append(removed, filename)
+			}
+		}
+		result = s.analyzer.UpdateFiles(changed, removed)
+		for _, filename := range removed {
+			delete(s.results, filename)
+			if s.published[filename] {
+				delete(s.published, filename)
+				s.publish(filename, nil)
+			}
+		}
+		for filename, res := range result.Results {
+			s.results[filename] = res
+		}
+	}
+	s.rescan = false
+	s.changed = make(map[string]bool)
+
+	for filename, res := range result.Results {
+		diagnostics := lspDiagnostics(filename, res)
+		if len(diagnostics) == 0 && !s.published[filename] {
+			continue
+		}
+		s.published[filename] = len(diagnostics) > 0
+		s.publish(filename, diagnostics)
+	}
+}
+
+func (s *lspServer) publish(filename string, diagnostics []lspDiagnostic) {
+	if diagnostics == nil {
+		diagnostics = []lspDiagnostic{}
+	}
+	s.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         pathToURI(filename),
+		"diagnostics": diagnostics,
+	})
+}
+
+func (s *lspServer) logError(err error) {
+	s.notify("window/logMessage", map[string]any{"type": 1, "message": err.Error()})
+}
+
+// codeActions returns the fixes and suppressions of the findings in the
+// requested lines. A pending analysis is run first, so that the edits
+// apply to the document as the editor has it.
+func (s *lspServer) codeActions(params lspCodeActionParams) []lspCodeAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.analyzeLocked()
+
+	filename := uriToPath(params.TextDocument.URI)
+	res, ok := s.results[filename]
+	if !ok {
+		return []lspCodeAction{}
+	}
+
+	actions := []lspCodeAction{}
+	for _, issue := range flattenResults(analyzer.WorkspaceAnalysisResult{Results: map[string]analyzer.AnalysisResult{filename: res}}) {
+		diagnostic := lspIssueDiagnostic(issue)
+		// Editors often ask for the cursor position only, so any finding
+		// on the requested lines qualifies.
+		if diagnostic.Range.End.Line < params.Range.Start.Line || diagnostic.Range.Start.Line > params.Range.End.Line {
+			continue
+		}
+		if issue.Fix != nil {
+			actions = append(actions, lspCodeAction{
+				Title:       issue.Fix.Title,
+				Kind:        "quickfix",
+				Diagnostics: []lspDiagnostic{diagnostic},
+				IsPreferred: true,
+				Edit:        lspEdit(issue.Fix),
+			})
+		}
+		if fix := analyzer.SuppressionFix(issue.CodeIssue, s.files[filename].Content); fix != nil {
+			actions = append(actions, lspCodeAction{
+				Title:       fix.Title,
+				Kind:        "quickfix",
+				Diagnostics: []lspDiagnostic{diagnostic},
+				Edit:        lspEdit(fix),
+			})
+		}
+	}
+	return actions
+}
+
+func lspDiagnostics(filename string, res analyzer.AnalysisResult) []lspDiagnostic {
+	var diagnostics []lspDiagnostic
+	for _, issue := range flattenResults(analyzer.WorkspaceAnalysisResult{Results: map[string]analyzer.AnalysisResult{filename: res}}) {
+		diagnostics = append(diagnostics, lspIssueDiagnostic(issue))
+	}
+	return diagnostics
+}
+
+func lspIssueDiagnostic(issue cliIssue) lspDiagnostic {
+	severity := 2
+	switch issue.Severity {
+	case analyzer.SeverityError:
+		severity = 1
+	case analyzer.SeverityInfo:
+		severity = 3
+	}
+	diagnostic := lspDiagnostic{
+		Range:    lspSpanRange(analyzer.Span{Line: issue.Line, Column: issue.Column, EndLine: issue.EndLine, EndColumn: issue.EndColumn}),
+		Severity: severity,
+		Code:     issue.RuleID,
+		Source:   analyzer.SARIFToolName,
+		Message:  fmt.Sprintf("unused %s: %s", issue.Category, issue.Text),
+	}
+	// An unused export is still used by its own file, so it is not faded.
+	if issue.Category != "export" {
+		diagnostic.Tags = []int{1} // unnecessary
+	}
+	return diagnostic
+}
+
+func lspEdit(fix *analyzer.Fix) lspWorkspaceEdit {
+	edit := lspWorkspaceEdit{Changes: make(map[string][]lspTextEdit)}
+	for _, e := range fix.Edits {
+		uri := pathToURI(e.File)
+		edit.Changes[uri] = append(edit.Changes[uri], lspTextEdit{Range: lspSpanRange(e.Span), NewText: e.NewText})
+	}
+	return edit
+}
+
+// lspSpanRange converts 1-based positions to the protocol's 0-based ones.
+// Both count columns in UTF-16 code units.
+func lspSpanRange(sp analyzer.Span) lspRange {
+	zero := func(n int) int {
+		if n > 0 {
+			return n - 1
+		}
+		return 0
+	}
+	return lspRange{
+		Start: lspPosition{Line: zero(sp.Line), Character: zero(sp.Column)},
+		End:   lspPosition{Line: zero(sp.EndLine), Character: zero(sp.EndColumn)},
+	}
+}
+
+func newAnalyzeFile(filename, content string) analyzer.AnalyzeFile {
+	sum := md5.Sum([]byte(content))
+	return analyzer.AnalyzeFile{Content: content, Filename: filename, Hash: hex.EncodeToString(sum[:])}
+}
+
+// uriToPath returns the slash-separated path of a file URI, or "" for
+// other schemes.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	p := u.Path
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	return p
+}
+
+func pathToURI(filename string) string {
+	p := filepath.ToSlash(filename)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
@@ -0,0 +1,201 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/selcuksarikoz/unused-code-analyzer/backend/analyzer"
+)
+
+// lspClient drives runLSP over pipes, as an editor does over stdio.
+type lspClient struct {
+	t        *testing.T
+	in       *io.PipeWriter
+	messages chan *lspMessage
+	done     chan int
+	nextID   int
+}
+
+func startLSP(t *testing.T) *lspClient {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &lspClient{t: t, in: inW, messages: make(chan *lspMessage, 64), done: make(chan int, 1)}
+	go func() {
+		c.done <- runLSP(cliOptions{exclude: defaultExcludeFolders}, inR, outW, io.Discard)
+		outW.Close()
+	}()
+	go func() {
+		r := &lspServer{in: bufio.NewReader(outR)}
+		for {
+			msg, err := r.read()
+			if err != nil {
+				close(c.messages)
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	return c
+}
+
+func (c *lspClient) send(method string, params any, request bool) {
+	c.t.Helper()
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if request {
+		c.nextID++
+		msg["id"] = c.nextID
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// expect returns the next message that is the reply to the last request
+// when method is "", or a notification of method otherwise.
+func (c *lspClient) expect(method string) *lspMessage {
+	c.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				c.t.Fatalf("server closed the connection waiting for %q", method)
+			}
+			if method == "" && string(msg.ID) == fmt.Sprint(c.nextID) || method != "" && msg.Method == method {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("no %q message", method)
+		}
+	}
+}
+
+// diagnostics waits for the diagnostics of uri and returns their messages.
+func (c *lspClient) diagnostics(uri string) []string {
+	c.t.Helper()
+	for {
+		var params struct {
+			URI         string          `json:"uri"`
+			Diagnostics []lspDiagnostic `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(c.expect("textDocument/publishDiagnostics").Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI != uri {
+			continue
+		}
+		messages := []string{}
+		for _, d := range params.Diagnostics {
+			messages = append(messages, d.Message)
+		}
+		return messages
+	}
+}
+
+func TestLSPRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.py"), []byte("import os\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	aURI, bURI := pathToURI(filepath.Join(root, "a.py")), pathToURI(filepath.Join(root, "b.py"))
+	document := func(uri, text string) map[string]any {
+		return map[string]any{"textDocument": map[string]any{"uri": uri, "text": text}}
+	}
+
+	c := startLSP(t)
+	c.send("initialize", map[string]any{"rootUri": pathToURI(root)}, true)
+	var init struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	data, _ := json.Marshal(c.expect("").Result)
+	if err := json.Unmarshal(data, &init); err != nil || init.Capabilities["textDocumentSync"] == nil {
+		t.Fatalf("initialize result %s", data)
+	}
+
+	c.send("initialized", map[string]any{}, false)
+	if got := c.diagnostics(aURI); len(got) != 1 {
+		t.Errorf("a.py on disk: diagnostics %v, want the unused import", got)
+	}
+
+	c.send("textDocument/didOpen", document(bURI, "import sys\n"), false)
+	if got := c.diagnostics(bURI); len(got) != 1 {
+		t.Errorf("b.py opened: diagnostics %v, want one", got)
+	}
+
+	changed := document(bURI, "")
+	changed["contentChanges"] = []map[string]any{{"text": "import sys\nimport re\n\nprint(sys.argv)\n"}}
+	c.send("textDocument/didChange", changed, false)
+	if got := c.diagnostics(bURI); len(got) != 1 || got[0] != "unused import: import re" {
+		t.Errorf("b.py changed: diagnostics %v, want the unused import re", got)
+	}
+
+	// b.py never existed on disk, so closing it removes it.
+	c.send("textDocument/didClose", document(bURI, ""), false)
+	if got := c.diagnostics(bURI); len(got) != 0 {
+		t.Errorf("b.py closed: diagnostics %v, want none", got)
+	}
+
+	c.send("shutdown", nil, true)
+	c.expect("")
+	c.send("exit", nil, false)
+	select {
+	case code := <-c.done:
+		if code != exitOK {
+			t.Errorf("exit code %d, want %d", code, exitOK)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestLSPReloadFile(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"a.py": "x = 1\n", "notes.txt": "notes\n", "node_modules/m.py": "y = 1\n"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := &lspServer{
+		opts:    cliOptions{root: root, exclude: defaultExcludeFolders},
+		files:   make(map[string]analyzer.AnalyzeFile),
+		open:    make(map[string]bool),
+		changed: make(map[string]bool),
+	}
+	tests := []struct {
+		name string
+		kept bool
+	}{
+		{"a.py", true},
+		{"notes.txt", false},
+		{"node_modules/m.py", false},
+		{"gone.py", false},
+	}
+	for _, tt := range tests {
+		filename := filepath.ToSlash(filepath.Join(root, filepath.FromSlash(tt.name)))
+		s.files[filename] = newAnalyzeFile(filename, "edited in the editor\n")
+		s.reloadFile(filename)
+		f, ok := s.files[filename]
+		switch {
+		case ok != tt.kept:
+			t.Errorf("%s: kept = %v after reloading, want %v", tt.name, ok, tt.kept)
+		case ok && f.Content == "edited in the editor\n":
+			t.Errorf("%s: still has the editor's content after reloading", tt.name)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"syscall/js"
+	"time"
+
+	"github.com/selcuksarikoz/unused-code-analyzer/backend/analyzer"
+)
+
+// jsonFunc exposes fn to JavaScript as a function taking and returning JSON
+// strings. It returns null when the argument is missing or malformed.
+func jsonFunc[Req, Res any](fn func(Req) Res) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		return callJSON(fn, args)
+	})
+}
+
+// analyzerFunc is jsonFunc for functions of the analyzer, which also
+// return null while analyzeWorkspaceAsync runs, see busy.
+func analyzerFunc[Req, Res any](fn func(Req) Res) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if busy.Load() {
+			return js.ValueOf(nil)
+		}
+		return callJSON(fn, args)
+	})
+}
+
+func callJSON[Req, Res any](fn func(Req) Res, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return js.ValueOf(nil)
+	}
+
+	var req Req
+	if err := json.Unmarshal([]byte(args[0].String()), &req); err != nil {
+		return js.ValueOf(nil)
+	}
+
+	jsonBytes, err := json.Marshal(fn(req))
+	if err != nil {
+		return js.ValueOf(nil)
+	}
+	return js.ValueOf(string(jsonBytes))
+}
+
+// busy is set while analyzeWorkspaceAsync runs. Its goroutine keeps the
+// analyzer locked while it yields to the event loop, so another call of
+// the analyzer would block the only thread for good; such calls fail
+// instead.
+var busy atomic.Bool
+
+// updateFilesRequest carries the arguments of MultiLangAnalyzer.UpdateFiles.
+type updateFilesRequest struct {
+	Changed []analyzer.AnalyzeFile `json:"changed"`
+	Removed []string               `json:"removed"`
+}
+
+// sarifRequest carries the arguments of analyzer.NewSARIFLog: the fields
+// of a workspace result and the root it was analyzed with.
+type sarifRequest struct {
+	analyzer.WorkspaceAnalysisResult
+	Root string `json:"root"`
+}
+
+// yieldInterval is how long an asynchronous analysis runs before it lets
+// the JavaScript event loop handle other events, such as an abort.
+const yieldInterval = 50 * time.Millisecond
+
+// analyzeWorkspaceAsync runs AnalyzeWorkspaceContext without blocking the
+// event loop for long. Its arguments are the request as JSON and an
+// optional object with onProgress, a function called with every progress
+// report, and signal, an AbortSignal. It returns a Promise of the result
+// as JSON, which is rejected with the signal's reason when aborted. Until
+// the Promise is settled, another analyzeWorkspaceAsync is rejected and
+// the other functions of the analyzer return null, see busy.
+func analyzeWorkspaceAsync(a *analyzer.MultiLangAnalyzer, args []js.Value) interface{} {
+	promise := js.Global().Get("Promise")
+	var req analyzer.WorkspaceAnalyzeRequest
+	if len(args) < 1 || json.Unmarshal([]byte(args[0].String()), &req) != nil {
+		return promise.Call("reject", js.Global().Get("TypeError").New("invalid workspace request"))
+	}
+	if !busy.CompareAndSwap(false, true) {
+		return promise.Call("reject", js.Global().Get("Error").New("a workspace analysis is already running"))
+	}
+	onProgress, signal := js.Undefined(), js.Undefined()
+	if len(args) > 1 && args[1].Type() == js.TypeObject {
+		onProgress, signal = args[1].Get("onProgress"), args[1].Get("signal")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	abort := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		cancel()
+		return nil
+	})
+	if signal.Type() == js.TypeObject {
+		if signal.Get("aborted").Truthy() {
+			cancel()
+		}
+		signal.Call("addEventListener", "abort", abort)
+	}
+
+	executor := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resolve, reject := args[0], args[1]
+		go func() {
+			defer busy.Store(false)
+			defer abort.Release()
+			defer cancel()
+			if signal.Type() == js.TypeObject {
+				defer signal.Call("removeEventListener", "abort", abort)
+			}
+
+			result, err := a.AnalyzeWorkspaceContext(ctx, req, progressFunc(onProgress))
+			if err != nil {
+				reject.Invoke(abortReason(signal))
+				return
+			}
+			jsonBytes, err := json.Marshal(result)
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+			resolve.Invoke(string(jsonBytes))
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return promise.New(executor)
+}
+
+// progressFunc forwards progress reports to onProgress, when it is a
+// function, and yields to the event loop every yieldInterval.
+func progressFunc(onProgress js.Value) analyzer.ProgressFunc {
+	last := time.Now()
+	return func(p analyzer.Progress) {
+		if onProgress.Type() == js.TypeFunction {
+			onProgress.Invoke(map[string]interface{}{"phase": p.Phase, "done": p.Done, "total": p.Total})
+		}
+		if time.Since(last) >= yieldInterval {
+			yieldToEventLoop()
+			last = time.Now()
+		}
+	}
+}
+
+// yieldToEventLoop blocks until the event loop has handled the pending
+// events. Node.js's setImmediate runs after them; time.Sleep would use a
+// timer, which Node.js may run again before timers that are already due.
+func yieldToEventLoop() {
+	schedule := js.Global().Get("setImmediate")
+	if schedule.Type() != js.TypeFunction {
+		schedule = js.Global().Get("setTimeout")
+	}
+	done := make(chan struct{})
+	resume := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		close(done)
+		return nil
+	})
+	defer resume.Release()
+	schedule.Invoke(resume)
+	<-done
+}
+
+// abortReason returns the reason of an aborted signal, or a generic error
+// when there is none.
+func abortReason(signal js.Value) js.Value {
+	if signal.Type() == js.TypeObject {
+		if reason := signal.Get("reason"); !reason.IsUndefined() {
+			return reason
+		}
+	}
+	return js.Global().Get("Error").New("analysis aborted")
+}
+
+func detectLanguageWrapper(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return js.ValueOf(string(analyzer.LangUnknown))
+	}
+	return js.ValueOf(string(analyzer.DetectLanguage(args[0].String())))
+}
+
+func main() {
+	a := analyzer.New()
+
+	js.Global().Set("analyzeCode", analyzerFunc(a.Analyze))
+	js.Global().Set("analyzeWorkspace", analyzerFunc(a.AnalyzeWorkspace))
+	js.Global().Set("analyzeWorkspaceAsync", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		return analyzeWorkspaceAsync(a, args)
+	}))
+	js.Global().Set("updateFiles", analyzerFunc(func(req updateFilesRequest) analyzer.WorkspaceAnalysisResult {
+		return a.UpdateFiles(req.Changed, req.Removed)
+	}))
+	js.Global().Set("explainSymbol", analyzerFunc(a.Explain))
+	js.Global().Set("exportParseCache", analyzerFunc(a.ExportParseCache))
+	js.Global().Set("importParseCache", analyzerFunc(a.ImportParseCache))
+	js.Global().Set("toSARIF", jsonFunc(func(req sarifRequest) *analyzer.SARIFLog {
+		return analyzer.NewSARIFLog(req.WorkspaceAnalysisResult, req.Root)
+	}))
+	js.Global().Set("detectLanguage", js.FuncOf(detectLanguageWrapper))
+
+	select {}
+}